@@ -0,0 +1,20 @@
+package rimnats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// AccountInfo returns the JetStream limits of the account and its current usage: stored
+// bytes, streams and consumers. Comparing Memory and Store against Limits.MaxMemory and
+// Limits.MaxStore lets operators alert before the account runs out of storage.
+func (n *rimNats) AccountInfo(ctx context.Context) (*jetstream.AccountInfo, error) {
+	info, err := n.js.AccountInfo(ctx)
+	if err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to get account info", "error", err)
+		return nil, wrapError("account info", "", "", err)
+	}
+
+	return info, nil
+}
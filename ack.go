@@ -0,0 +1,40 @@
+package rimnats
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// startInProgress periodically tells the server that m is still being worked on, so it is
+// not redelivered while a slow handler runs. The returned function stops the heartbeat and
+// must be called before m is acknowledged.
+func (n *rimNats) startInProgress(m jetstream.Msg, interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.InProgress(); err != nil {
+					if n.cfg.Debug {
+						n.loggR.Info("🚨 [ rimnats ]: failed to extend ack wait", "subject", m.Subject(), "error", err)
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,104 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// AnyRoute pairs the factory of a payload type carried in a google.protobuf.Any with the
+// handler processing it.
+type AnyRoute struct {
+	Factory func() proto.Message // Creates instances of the payload type
+	Handler ProtoHandler         // Processes the unpacked payload
+}
+
+// SubscribeAny subscribes to envelopes carrying their payload in a google.protobuf.Any field
+// and dispatches each message to the route registered for the payload's type. Routes are keyed
+// by type URL, e.g. "type.googleapis.com/rimdesk.rimnats.v1.ProductCreated", or by the fully
+// qualified message name alone. The handler receives the unpacked payload. Envelopes whose
+// payload has no route fail with ErrNoHandler and are terminated.
+//
+// Parameters:
+//   - subject: The NATS subject to subscribe to
+//   - stream: The stream name for the subscription
+//   - durable: The durable name for the subscription
+//   - factory: A function that creates new instances of the envelope message type, which may
+//     be google.protobuf.Any itself
+//   - routes: Factories and handlers keyed by payload type URL or message name
+//   - opts: Optional subscription options
+//
+// Returns:
+//   - *Subscription: Handle used to stop the subscription
+//   - error: Returns an error if the subscription setup fails
+func (n *rimNats) SubscribeAny(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	routes map[string]AnyRoute,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return n.Subscribe(ctx, subject, stream, durable, factory, dispatchAny(routes), opts...)
+}
+
+// dispatchAny returns a handler unpacking the Any payload of envelopes and routing it to
+// routes by type.
+func dispatchAny(routes map[string]AnyRoute) ProtoHandler {
+	return func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+		payload, ok := findAny(msg)
+		if !ok {
+			return fmt.Errorf("%w: %s carries no Any payload", ErrNoHandler, msg.ProtoReflect().Descriptor().FullName())
+		}
+
+		route, ok := routes[payload.GetTypeUrl()]
+		if !ok {
+			route, ok = routes[string(payload.MessageName())]
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrNoHandler, payload.GetTypeUrl())
+		}
+
+		unpacked := route.Factory()
+		if err := payload.UnmarshalTo(unpacked); err != nil {
+			return unmarshalError(err)
+		}
+
+		return route.Handler(ctx, unpacked, m)
+	}
+}
+
+// findAny returns msg when it is an Any, or else the first populated Any field of msg.
+func findAny(msg proto.Message) (*anypb.Any, bool) {
+	if payload, ok := msg.(*anypb.Any); ok {
+		return payload, true
+	}
+
+	var payload *anypb.Any
+	msg.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.Message() == nil || field.IsList() || field.IsMap() {
+			return true
+		}
+
+		if field.Message().FullName() != anyFullName {
+			return true
+		}
+
+		if found, ok := value.Message().Interface().(*anypb.Any); ok {
+			payload = found
+			return false
+		}
+
+		return true
+	})
+
+	return payload, payload != nil
+}
+
+// anyFullName is the fully qualified name of google.protobuf.Any.
+const anyFullName protoreflect.FullName = "google.protobuf.Any"
@@ -0,0 +1,106 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStreamNearCapacity was returned by publishes using WithCapacityWarning.
+//
+// Deprecated: WithCapacityWarning reports through its callback and publishes no longer return this error.
+var ErrStreamNearCapacity = errors.New("rimnats: stream near capacity")
+
+// CapacityWarning describes a stream whose usage reached the threshold of WithCapacityWarning.
+type CapacityWarning struct {
+	Stream   string // Stream the message was published to
+	Subject  string // Subject the message was published on
+	Bytes    uint64 // Bytes stored in the stream
+	MaxBytes int64  // Stream's MaxBytes limit, zero or negative when unlimited
+	Msgs     uint64 // Messages stored in the stream
+	MaxMsgs  int64  // Stream's MaxMsgs limit, zero or negative when unlimited
+}
+
+// capacityWarning checks the stream's usage after publishing.
+type capacityWarning struct {
+	threshold float64               // Fraction of the stream's limits at which onWarning is called
+	onWarning func(CapacityWarning) // Callback invoked on every publish at or above the threshold
+}
+
+// WithCapacityWarning checks the stream's usage after publishing and calls onWarning when it is at
+// or above threshold, a fraction of the stream's MaxBytes or MaxMsgs limit such as 0.8, so producers
+// can slow down before publishes start failing. The publish itself is unaffected and its result is
+// returned as usual. Streams without limits never trigger the warning. When onWarning is nil the
+// warning is logged instead. The check applies to every publish method and costs an extra stream
+// info request per message. PublishAsync and PublishBatch look the stream up by subject and check
+// without waiting for the acknowledgement, so the usage may not include the message yet.
+func WithCapacityWarning(threshold float64, onWarning func(CapacityWarning)) PublishOption {
+	return func(o *publishOptions) {
+		o.capacity = &capacityWarning{threshold: threshold, onWarning: onWarning}
+	}
+}
+
+// checkCapacity calls the warning's callback, or logs, when the usage of stream is at or above the
+// warning's threshold. An empty stream is looked up by subject. Lookup failures are only logged in
+// debug mode since the message was already published.
+func (n *rimNats) checkCapacity(ctx context.Context, warning *capacityWarning, stream, subject string) {
+	usage, err := n.streamCapacity(ctx, stream, subject)
+	if err != nil {
+		if n.cfg.Debug {
+			n.loggR.Info("📈 [ rimnats ]: failed to check stream capacity", "stream", stream, "subject", subject, "error", err)
+		}
+		return
+	}
+
+	if !usage.exceeds(warning.threshold) {
+		return
+	}
+
+	if warning.onWarning == nil {
+		n.loggR.Warn("📈 [ rimnats ]: stream near capacity",
+			"stream", usage.Stream,
+			"bytes", usage.Bytes,
+			"max_bytes", usage.MaxBytes,
+			"msgs", usage.Msgs,
+			"max_msgs", usage.MaxMsgs,
+			"threshold", warning.threshold,
+		)
+		return
+	}
+
+	warning.onWarning(usage)
+}
+
+// streamCapacity looks up the usage and limits of stream, or of the stream capturing subject when stream is empty.
+func (n *rimNats) streamCapacity(ctx context.Context, stream, subject string) (CapacityWarning, error) {
+	if stream == "" {
+		name, err := n.js.StreamNameBySubject(ctx, subject)
+		if err != nil {
+			return CapacityWarning{}, err
+		}
+		stream = name
+	}
+
+	jetStream, err := n.js.Stream(ctx, stream)
+	if err != nil {
+		return CapacityWarning{}, err
+	}
+
+	info := jetStream.CachedInfo()
+	return CapacityWarning{
+		Stream:   stream,
+		Subject:  subject,
+		Bytes:    info.State.Bytes,
+		MaxBytes: info.Config.MaxBytes,
+		Msgs:     info.State.Msgs,
+		MaxMsgs:  info.Config.MaxMsgs,
+	}, nil
+}
+
+// exceeds reports whether the stream's usage is at or above threshold of either of its limits.
+func (w CapacityWarning) exceeds(threshold float64) bool {
+	if w.MaxBytes > 0 && float64(w.Bytes) >= threshold*float64(w.MaxBytes) {
+		return true
+	}
+
+	return w.MaxMsgs > 0 && float64(w.Msgs) >= threshold*float64(w.MaxMsgs)
+}
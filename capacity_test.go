@@ -0,0 +1,116 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCapacityWarning(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	_, err := client.CreateStream(ctx, jetstream.StreamConfig{Name: "limited", Subjects: []string{"limited.>"}, MaxMsgs: 10})
+	if err != nil {
+		t.Fatalf("create stream: %v", err)
+	}
+	createTestStream(t, client, "unlimited", "unlimited.>")
+
+	var mu sync.Mutex
+	var warnings []CapacityWarning
+	warn := WithCapacityWarning(0.8, func(warning CapacityWarning) {
+		mu.Lock()
+		defer mu.Unlock()
+		warnings = append(warnings, warning)
+	})
+	warned := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(warnings)
+	}
+
+	for i := 1; i <= 10; i++ {
+		ack, err := client.Publish(ctx, "limited.created", &v1.ProductCreated{}, warn)
+		if err != nil {
+			t.Fatalf("publish %d: %v", i, err)
+		}
+		if ack.Sequence != uint64(i) {
+			t.Fatalf("publish %d: ack = %+v, want the message to be stored", i, ack)
+		}
+
+		// From the 8th message on the stream is at 80% of MaxMsgs
+		if want := max(i-7, 0); warned() != want {
+			t.Fatalf("publish %d: %d warnings, want %d", i, warned(), want)
+		}
+	}
+
+	got := warnings[0]
+	if got.Stream != "limited" || got.Subject != "limited.created" || got.Msgs != 8 || got.MaxMsgs != 10 {
+		t.Errorf("warning = %+v, want 8 of 10 messages on limited", got)
+	}
+
+	for range 20 {
+		if _, err := client.Publish(ctx, "unlimited.created", &v1.ProductCreated{}, warn); err != nil {
+			t.Fatalf("publish to unlimited stream: %v", err)
+		}
+	}
+	if warned() != 3 {
+		t.Errorf("%d warnings after publishing to the unlimited stream, want 3", warned())
+	}
+}
+
+func TestCapacityWarningAsync(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	_, err := client.CreateStream(ctx, jetstream.StreamConfig{Name: "limited", Subjects: []string{"limited.>"}, MaxMsgs: 10})
+	if err != nil {
+		t.Fatalf("create stream: %v", err)
+	}
+
+	var mu sync.Mutex
+	var warnings []CapacityWarning
+	warn := WithCapacityWarning(0.5, func(warning CapacityWarning) {
+		mu.Lock()
+		defer mu.Unlock()
+		warnings = append(warnings, warning)
+	})
+
+	msgs := make([]proto.Message, 6)
+	for i := range msgs {
+		msgs[i] = &v1.ProductCreated{}
+	}
+	if err := client.PublishBatch(ctx, "limited.created", msgs); err != nil {
+		t.Fatalf("publish batch: %v", err)
+	}
+
+	future, err := client.PublishAsync(ctx, "limited.created", &v1.ProductCreated{}, warn)
+	if err != nil {
+		t.Fatalf("publish async: %v", err)
+	}
+	select {
+	case <-future.Ok():
+	case err := <-future.Err():
+		t.Fatalf("publish async: %v", err)
+	}
+
+	if err := client.PublishBatch(ctx, "limited.created", msgs[:2], warn); err != nil {
+		t.Fatalf("publish batch: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(warnings) != 3 {
+		t.Fatalf("%d warnings, want one per publish", len(warnings))
+	}
+	for _, warning := range warnings {
+		if warning.Stream != "limited" || warning.Msgs < 6 {
+			t.Errorf("warning = %+v, want at least 6 messages on limited", warning)
+		}
+	}
+}
@@ -5,87 +5,274 @@ package rimnats
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/beego/beego/v2/core/logs"
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nats.go/micro"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type Client interface {
+	AddService(config micro.Config) (*Service, error)
 	Close()
-	Connect()
+	Connect() error
+	Drain(ctx context.Context) error
+	DrainStreamConsumers(ctx context.Context, stream string) error
+	Shutdown(ctx context.Context) error
+	Status() nats.Status
+	HealthCheck(ctx context.Context) error
+	ServerInfo() (version string, jetStreamEnabled bool, err error)
+	SupportsPerMessageTTL() bool
+	SupportsSubjectTransforms() bool
+	OnDisconnect(fn func(*nats.Conn, error))
+	OnReconnect(fn func(*nats.Conn))
+	OnClosed(fn func(*nats.Conn))
+	Fetch(ctx context.Context, subject, stream, durable string, batch int, factory func() proto.Message, opts ...SubscribeOption) ([]DecodedMsg, error)
 	GetEngine() *rimNats
+	GetLastMsg(ctx context.Context, stream, subject string) (proto.Message, *jetstream.RawStreamMsg, error)
+	GetMsg(ctx context.Context, stream string, seq uint64, factory func() proto.Message) (proto.Message, *jetstream.RawStreamMsg, error)
 	JetStream() jetstream.JetStream
-	CreateStream(ctx context.Context, config jetstream.StreamConfig) error
-	Publish(ctx context.Context, subject string, msg proto.Message, opts ...jetstream.PublishOpt) error
-	Reply(subject string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error)) error
-	Request(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration) (proto.Message, error)
-	Subscribe(ctx context.Context, subject, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...jetstream.PullConsumeOpt) error
+	AccountInfo(ctx context.Context) (*jetstream.AccountInfo, error)
+	ConsumerInfo(ctx context.Context, stream, durable string) (*jetstream.ConsumerInfo, error)
+	CreateKVBucket(ctx context.Context, config jetstream.KeyValueConfig) (*KVStore, error)
+	KV(ctx context.Context, bucket string) (*KVStore, error)
+	CreateStream(ctx context.Context, config jetstream.StreamConfig) (jetstream.Stream, error)
+	CreateMirror(ctx context.Context, name, sourceStream string, opts ...StreamOption) (jetstream.Stream, error)
+	CreateWorkQueue(ctx context.Context, name string, subjects []string, opts ...StreamOption) (jetstream.Stream, error)
+	CreateSourcedStream(ctx context.Context, name string, sources []string, opts ...StreamOption) (jetstream.Stream, error)
+	AddStreamSubject(ctx context.Context, name, subject string) error
+	DeleteStream(ctx context.Context, name string) error
+	RemoveStreamSubject(ctx context.Context, name, subject string) error
+	PurgeStream(ctx context.Context, name string, opts ...jetstream.StreamPurgeOpt) error
+	PendingMessages(ctx context.Context, stream, durable string) (uint64, error)
+	PauseConsumer(ctx context.Context, stream, durable string, until time.Time) (*jetstream.ConsumerPauseResponse, error)
+	ResumeConsumer(ctx context.Context, stream, durable string) (*jetstream.ConsumerPauseResponse, error)
+	Publish(ctx context.Context, subject string, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error)
+	PublishAsync(ctx context.Context, subject string, msg proto.Message, opts ...PublishOption) (jetstream.PubAckFuture, error)
+	PublishAsyncComplete() <-chan struct{}
+	Flush(ctx context.Context) error
+	PublishMsg(ctx context.Context, msg *Message, opts ...PublishOption) (*jetstream.PubAck, error)
+	PublishToStream(ctx context.Context, stream, subject string, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error)
+	PublishBatch(ctx context.Context, subject string, msgs []proto.Message, opts ...PublishOption) error
+	Pull(ctx context.Context, subject, stream, durable string, batch int, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (int, error)
+	Reply(subject string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...ReplyOption) error
+	ReplyQueue(subject, queue string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...ReplyOption) error
+	ReplyStreaming(subject string, reqFactory func() proto.Message, handler StreamingHandler, opts ...ReplyOption) error
+	RegisterFactory(subject string, factory func() proto.Message)
+	Replay(ctx context.Context, stream, subject string, startSeq uint64, handler ProtoHandler, opts ...SubscribeOption) error
+	Request(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, opts ...RequestOption) (proto.Message, error)
+	RequestMany(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, maxResponses int, window time.Duration) ([]proto.Message, error)
+	RequestFull(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, opts ...RequestOption) (*Response, error)
+	Subscribe(ctx context.Context, subject, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeChan(ctx context.Context, subject, stream, durable string, factory func() proto.Message, opts ...SubscribeOption) (<-chan DecodedMsg, error)
+	SubscribeMulti(ctx context.Context, subjects []string, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error)
+	SubscribePartition(ctx context.Context, subject string, partition int, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeAny(ctx context.Context, subject, stream, durable string, factory func() proto.Message, routes map[string]AnyRoute, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeOneof(ctx context.Context, subject, stream, durable string, factory func() proto.Message, handlers map[protoreflect.Name]ProtoHandler, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeRegistered(ctx context.Context, subject, stream, durable string, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeRenamed(ctx context.Context, oldSubject, newSubject, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error)
+	SubscribeRouter(ctx context.Context, subject, stream, durable string, router *Router, opts ...SubscribeOption) (*Subscription, error)
+	TailLast(ctx context.Context, stream, subject string, count int, factory func() proto.Message) ([]proto.Message, error)
+	Use(middlewares ...Middleware)
 }
 
+// Ensure the concrete client satisfies the Client interface at compile time.
+var _ Client = (*rimNats)(nil)
+
 // Rimnats represents a NATS client with JetStream support.
 type rimNats struct {
-	conn  *nats.Conn          // Connection to the NATS server
-	cfg   *nexorConfig        // Configuration for the NATS client
-	loggR *logs.BeeLogger     // Beego logger for logging
-	js    jetstream.JetStream // JetStream context for pub/sub operations
+	conn       *nats.Conn          // Connection to the NATS server
+	cfg        *nexorConfig        // Configuration for the NATS client
+	loggR      Logger              // Logger used for all client logs
+	js         jetstream.JetStream // JetStream context for pub/sub operations
+	outbox     *outbox             // Buffer for publishes made while disconnected, nil when disabled
+	pool       *connPool           // Connections publishes are spread across, nil when pooling is disabled
+	subs       *subscriptionSet    // Subscriptions and consumers currently active
+	hooks      *connectionHooks    // Callbacks for connection state changes
+	factories  *FactoryRegistry    // Protobuf factories registered by subject
+	middleware middlewareChain     // Middlewares applied around subscription handlers
+	inflight   sync.WaitGroup      // Subscription and reply handlers currently running
+	requests   dedupFlight         // Deduplicated requests currently being answered
 }
 
-func (n *rimNats) CreateStream(ctx context.Context, config jetstream.StreamConfig) error {
-	_, err := n.js.CreateOrUpdateStream(ctx, config)
-	if err != nil {
-		n.loggR.Error("🚨 Failed to create stream: %v", err)
+// CreateStream creates the stream described by config, or updates it if it already exists.
+// It returns the stream handle so callers can add consumers without fetching it again.
+// The configuration is validated first, so overlapping subjects are reported with a
+// descriptive error matching ErrInvalidStreamConfig.
+// Creating a stream is idempotent: when several replicas race to create the same stream the
+// losers retry with a short backoff and pick up the existing stream instead of failing.
+func (n *rimNats) CreateStream(ctx context.Context, config jetstream.StreamConfig) (jetstream.Stream, error) {
+	if err := validateStreamConfig(config); err != nil {
+		return nil, wrapError("create stream", config.Name, "", err)
 	}
 
-	return nil
+	backoff := 50 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		stream, err := n.js.CreateOrUpdateStream(ctx, config)
+		if err == nil {
+			return stream, nil
+		}
+
+		if attempt == createStreamAttempts || !isTransientCreateError(err) {
+			n.loggR.Error("🚨 Failed to create stream", "stream", config.Name, "attempts", attempt, "error", err)
+			return nil, wrapError("create stream", config.Name, "", err)
+		}
+
+		if n.cfg.Debug {
+			n.loggR.Info("🔁 [ rimnats ]: retrying stream creation", "stream", config.Name, "attempt", attempt, "error", err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, wrapError("create stream", config.Name, "", ctx.Err())
+		}
+
+		backoff *= 2
+	}
+}
+
+// createStreamAttempts is the number of times CreateStream tries before giving up on transient errors.
+const createStreamAttempts = 5
+
+// isTransientCreateError reports whether a stream creation failure is caused by a concurrent
+// create or a JetStream leader election and is likely to succeed when retried.
+func isTransientCreateError(err error) bool {
+	return errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) ||
+		errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, nats.ErrTimeout)
 }
 
 func (n *rimNats) GetEngine() *rimNats {
 	return n
 }
 
-func (n *rimNats) Connect() {
-	conn, err := nats.Connect(n.cfg.Url, n.cfg.Opts...)
+// Connect establishes the connection to the NATS server and initializes the JetStream context.
+// It returns an error instead of terminating the process so callers can retry or fall back.
+func (n *rimNats) Connect() error {
+	opts, err := n.cfg.connectOptions()
+	if err != nil {
+		return wrapError("connect", "", "", err)
+	}
+
+	// Connection events are dispatched to the callbacks registered with OnDisconnect,
+	// OnReconnect and OnClosed, so these handlers take precedence over raw NATS options.
+	// Pooled connections are opened without them.
+	conn, err := nats.Connect(n.cfg.Url, append(opts, n.hooks.options()...)...)
 	if err != nil {
 		if n.cfg.Debug {
-			n.loggR.Error("🔌 Failed to connect to NATS: %v", err)
-			os.Exit(1)
+			n.loggR.Error("🔌 Failed to connect to NATS", "url", n.cfg.Url, "error", err)
 		}
-		os.Exit(1)
+
+		return wrapError("connect", "", "", err)
 	}
 
-	js, err := jetstream.New(conn)
+	js, err := n.newJetStream(conn)
 	if err != nil {
 		if n.cfg.Debug {
-			n.loggR.Error("🔌 Failed to connect to Jetstream: %v 🔌", err)
-			os.Exit(1)
+			n.loggR.Error("🔌 Failed to connect to Jetstream 🔌", "error", err)
 		}
 
 		conn.Close()
-		os.Exit(1)
+		return wrapError("connect", "", "", err)
 	}
 
 	n.conn = conn
 	n.js = js
 
+	if size := n.poolSize(); size > 1 {
+		pool, err := n.connectPool(size, opts)
+		if err != nil {
+			if n.cfg.Debug {
+				n.loggR.Error("🔌 Failed to open the connection pool", "size", size, "error", err)
+			}
+
+			conn.Close()
+			return wrapError("connect", "", "", err)
+		}
+		n.pool = pool
+	}
+
 	if n.cfg.Debug {
 		n.loggR.Info("🚀 Connected to NATS server successful")
 	}
+
+	// Messages published before the first Connect are buffered like those published while disconnected
+	if n.outbox != nil {
+		n.replayOutbox(conn)
+	}
+
+	return nil
+}
+
+// newJetStream returns the JetStream context of conn, bound to the configured domain.
+func (n *rimNats) newJetStream(conn *nats.Conn) (jetstream.JetStream, error) {
+	if n.cfg.Domain != "" {
+		return jetstream.NewWithDomain(conn, n.cfg.Domain)
+	}
+
+	return jetstream.New(conn)
+}
+
+// poolSize returns the number of connections to publish through, capped at MaxConn.
+func (n *rimNats) poolSize() int {
+	size := n.cfg.PoolSize
+	if n.cfg.MaxConn > 0 && size > n.cfg.MaxConn {
+		size = n.cfg.MaxConn
+	}
+
+	return size
 }
 
 // nexorConfig holds the configuration parameters for the NATS client.
 type nexorConfig struct {
-	Url        string        // Url is the address of the NATS server for client connection.
-	ClientName string        // Name of the client used for connection identification
-	Debug      bool          // Enable debug mode for verbose logging
-	MaxConn    int           // Maximum number of allowed connections
-	MaxRecon   int           // Maximum number of reconnection attempts
-	ReconWait  int           // Time to wait between reconnection attempts in seconds
-	Opts       []nats.Option // Opts specifies additional NATS options for configuring the client connection or behavior.
+	Url          string                        // Url is the address of the NATS server for client connection.
+	ClientName   string                        // Name of the client used for connection identification
+	Debug        bool                          // Enable debug mode for verbose logging
+	MaxConn      int                           // Maximum number of allowed connections
+	PoolSize     int                           // Number of connections publishes are spread across, capped at MaxConn
+	MaxRecon     int                           // Maximum number of reconnection attempts
+	ReconWait    int                           // Time to wait between reconnection attempts in seconds
+	Opts         []nats.Option                 // Opts specifies additional NATS options for configuring the client connection or behavior.
+	EventCodec   Codec                         // Codec used to encode and decode events on Publish and Subscribe
+	RPCCodec     Codec                         // Codec used to encode and decode requests and replies on Request and Reply
+	OutboxSize   int                           // Maximum number of publishes buffered while disconnected, zero disables the outbox
+	OutboxTTL    time.Duration                 // Maximum age of a buffered publish before it is dropped instead of replayed
+	Metrics      *Metrics                      // Prometheus metrics updated by the client, nil disables metrics
+	CredsFile    string                        // Path to a NATS credentials file
+	Token        string                        // Token used for token authentication
+	User         string                        // User name used for user/password authentication
+	Password     string                        // Password used for user/password authentication
+	NKeySeedFile string                        // Path to an NKey seed file
+	TLSCertFile  string                        // Client certificate for mutual TLS
+	TLSKeyFile   string                        // Client key for mutual TLS
+	TLSCAFile    string                        // CA bundle used to verify the server certificate
+	TLSConfig    *tls.Config                   // Complete TLS configuration, takes precedence over the TLS files
+	Domain       string                        // JetStream domain to bind the JetStream context to
+	InboxPrefix  string                        // Prefix of the reply inboxes used by requests, empty for "_INBOX"
+	PublishWait  time.Duration                 // Maximum time Publish waits for a lost connection to recover
+	DryRun       bool                          // Validate publishes without sending them
+	Validators   []Validator                   // Checks run on every message before it is published
+	RateLimits   []publishLimit                // Client-side limits on the publish rate per subject
+	Logger       Logger                        // Logger used by the client, defaults to a Beego console logger
+	Factories    *FactoryRegistry              // Registry of protobuf factories by subject, defaults to a registry per client
+	Tracer       trace.Tracer                  // Tracer recording spans, defaults to a no-op tracer
+	Propagator   propagation.TextMapPropagator // Propagator carrying trace context in headers, defaults to the global propagator
+	registerer   prometheus.Registerer         // Registry Metrics are registered with by WithObservability
+	clock        func() time.Time              // Clock used to age buffered publishes
 }
 
 // getConfig retrieves the configuration from environment variables and returns
@@ -93,7 +280,7 @@ type nexorConfig struct {
 func getConfig() *nexorConfig {
 	var debugMode = false
 	var clientName = "Rimnats"
-	var maxConn, maxWait = 5, 5
+	var maxConnections, maxReconnects, reconnectWait = 5, 10, 5
 	if debugModeValue, found := os.LookupEnv("RIMNATS.DEBUG"); found {
 		debugMode = debugModeValue == "true"
 	}
@@ -102,23 +289,34 @@ func getConfig() *nexorConfig {
 		clientName = clientNameValue
 	}
 
-	if maxConnValue := os.Getenv("RIMNATS.MAX_CONNECTIONS"); maxConnValue != "" {
-		maxConn, _ = strconv.Atoi(maxConnValue)
+	if value, err := strconv.Atoi(os.Getenv("RIMNATS.MAX_CONNECTIONS")); err == nil {
+		maxConnections = value
 	}
 
-	if maxWaitValue := os.Getenv("RIMNATS.MAX_RECONNECT_WAIT"); maxWaitValue != "" {
-		maxWait, _ = strconv.Atoi(maxWaitValue)
+	// A negative value reconnects forever
+	if value, err := strconv.Atoi(os.Getenv("RIMNATS.MAX_RECONNECTS")); err == nil {
+		maxReconnects = value
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("RIMNATS.MAX_RECONNECT_WAIT")); err == nil {
+		reconnectWait = value
 	}
 
 	return &nexorConfig{
 		ClientName: clientName,
+		CredsFile:  os.Getenv("RIMNATS.CREDS_FILE"),
 		Debug:      debugMode,
-		MaxConn:    maxConn,
-		ReconWait:  maxWait,
+		MaxConn:    maxConnections,
+		MaxRecon:   maxReconnects,
+		ReconWait:  reconnectWait,
+		EventCodec: ProtoCodec{},
+		RPCCodec:   ProtoCodec{},
+		Tracer:     noop.NewTracerProvider().Tracer(instrumentationName),
+		clock:      time.Now,
 	}
 }
 
-func getLogger() *logs.BeeLogger {
+func getLogger() Logger {
 	beeLogger := logs.NewLogger(10000)
 
 	beeLogger.SetLogger(
@@ -127,38 +325,205 @@ func getLogger() *logs.BeeLogger {
 	)
 
 	beeLogger.EnableFuncCallDepth(true)
-	beeLogger.SetLogFuncCallDepth(3)
+	beeLogger.SetLogFuncCallDepth(4)
 
 	beeLogger.Async(1000)
 
-	return beeLogger
+	return NewBeegoLogger(beeLogger)
 }
 
 // New creates a new Rimnats instance connected to the specified NATS server.
-// It accepts a URL string and optional NATS options. If no options are provided,
-// it uses default configuration values from environment variables.
+// It accepts a URL string and optional client options. The URL may be a comma-separated
+// list of cluster members. The connection name, maximum reconnects and reconnect wait
+// default to the values from the environment variables; NATS options passed with
+// WithNatsOptions are applied after them and take precedence.
 // Returns a configured Rimnats instance and any error encountered during connection.
-func New(url string, opts ...nats.Option) Client {
+//
+// Migration: New used to take raw NATS options, New(url, nats.Name("x")). Such calls no
+// longer compile; wrap the options with WithNatsOptions, New(url, WithNatsOptions(nats.Name("x"))),
+// or call NewWithNatsOptions, which keeps the previous signature.
+func New(url string, opts ...Option) Client {
 	cfg := getConfig()
 	cfg.Url = url
-	cfg.Opts = opts
 
-	if len(opts) == 0 {
-		opts = []nats.Option{
-			nats.Name(cfg.ClientName),
-			nats.MaxReconnects(cfg.MaxRecon),
-			nats.ReconnectWait(time.Duration(cfg.ReconWait) * time.Second),
-		}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
-	return &rimNats{cfg: cfg, loggR: getLogger()}
+	// The defaults come first so raw NATS options passed with WithNatsOptions override them
+	cfg.Opts = append([]nats.Option{
+		nats.Name(cfg.ClientName),
+		nats.MaxReconnects(cfg.MaxRecon),
+		nats.ReconnectWait(time.Duration(cfg.ReconWait) * time.Second),
+	}, cfg.Opts...)
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = getLogger()
+	}
+
+	factories := cfg.Factories
+	if factories == nil {
+		factories = NewFactoryRegistry()
+	}
+
+	client := &rimNats{cfg: cfg, loggR: logger, hooks: &connectionHooks{}, factories: factories}
+	client.registerMetrics()
+	client.subs = newSubscriptionSet(cfg.Metrics)
+	client.OnReconnect(func(conn *nats.Conn) {
+		go client.restoreSubscriptions()
+	})
+	if cfg.OutboxSize > 0 {
+		client.outbox = newOutbox(cfg.OutboxSize, cfg.OutboxTTL, cfg.clock)
+		client.OnReconnect(func(conn *nats.Conn) {
+			go client.replayOutbox(conn)
+		})
+	}
+
+	return client
+}
+
+// NewWithNatsOptions creates a new Rimnats instance with raw NATS options, like New did
+// before it took client options. It is equivalent to New(url, WithNatsOptions(opts...)).
+func NewWithNatsOptions(url string, opts ...nats.Option) Client {
+	return New(url, WithNatsOptions(opts...))
+}
+
+// NewCluster creates a new Rimnats instance for a NATS cluster reachable at any of urls.
+// The client connects to one member and fails over to the others on reconnect. The
+// JetStream context is bound to the connection rather than a server, so it keeps working
+// after the connection moves to another node.
+func NewCluster(urls []string, opts ...Option) Client {
+	return New(strings.Join(urls, ","), opts...)
 }
 
 // Close safely closes the NATS connection.
 func (n *rimNats) Close() {
+	if n.pool != nil {
+		n.pool.close()
+	}
+
 	if n.conn != nil && !n.conn.IsClosed() {
 		n.conn.Close()
 	}
+
+	n.subs.clear()
+}
+
+// Drain gracefully shuts the client down without dropping in-flight messages.
+// Consumers stop pulling new messages and finish delivering the ones already buffered,
+// then the connection drains its remaining subscriptions and closes. If ctx is done
+// before draining completes the connection is closed immediately and ctx's error is returned.
+func (n *rimNats) Drain(ctx context.Context) error {
+	if n.conn == nil || n.conn.IsClosed() {
+		return nil
+	}
+
+	defer n.subs.clear()
+
+	if n.pool != nil {
+		defer n.pool.close()
+	}
+
+	for _, s := range n.subs.list() {
+		consume := s.drain()
+		if consume == nil {
+			continue
+		}
+
+		select {
+		case <-consume.Closed():
+		case <-ctx.Done():
+			n.conn.Close()
+			return wrapError("drain", s.stream, s.subject, ctx.Err())
+		}
+	}
+
+	if err := n.conn.Drain(); err != nil {
+		return wrapError("drain", "", "", err)
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !n.conn.IsClosed() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			n.conn.Close()
+			return wrapError("drain", "", "", ctx.Err())
+		}
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🛑 [ rimnats ]: connection drained and closed")
+	}
+
+	return nil
+}
+
+// Shutdown stops every subscription from receiving new messages, waits for the handlers
+// already running to finish and then closes the connection, so a process receiving SIGTERM
+// completes its current work instead of leaving it to be redelivered. If ctx is done before
+// the handlers finish the connection is closed immediately and ctx's error is returned.
+func (n *rimNats) Shutdown(ctx context.Context) error {
+	for _, s := range n.subs.list() {
+		s.Stop()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		n.inflight.Wait()
+		close(done)
+	}()
+
+	defer n.Close()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return wrapError("shutdown", "", "", ctx.Err())
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🛑 [ rimnats ]: in-flight handlers finished, connection closed")
+	}
+
+	return nil
+}
+
+// DrainStreamConsumers stops the client's consumers bound to stream without dropping in-flight
+// messages, leaving every other subscription and the connection running. Each consumer stops
+// pulling new messages and finishes delivering the ones already buffered. If ctx is done before
+// draining completes the remaining consumers are stopped immediately and ctx's error is returned.
+func (n *rimNats) DrainStreamConsumers(ctx context.Context, stream string) error {
+	var draining []jetstream.ConsumeContext
+	for _, s := range n.subs.list() {
+		if s.stream != stream {
+			continue
+		}
+
+		if consume := s.drain(); consume != nil {
+			draining = append(draining, consume)
+		}
+	}
+
+	for i, consume := range draining {
+		select {
+		case <-consume.Closed():
+		case <-ctx.Done():
+			for _, remaining := range draining[i:] {
+				remaining.Stop()
+			}
+			return wrapError("drain stream consumers", stream, "", ctx.Err())
+		}
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🛑 [ rimnats ]: stream consumers drained", "stream", stream, "count", len(draining))
+	}
+
+	return nil
 }
 
 // JetStream exposes the underlying JetStream context
@@ -0,0 +1,318 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+// freePorts returns n distinct TCP ports that are free on the loopback interface.
+func freePorts(t *testing.T, n int) []int {
+	t.Helper()
+
+	ports := make([]int, n)
+	for i := range ports {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		defer l.Close()
+
+		ports[i] = l.Addr().(*net.TCPAddr).Port
+	}
+
+	return ports
+}
+
+// startCluster starts an embedded NATS cluster of size servers with JetStream enabled for the
+// duration of t.
+func startCluster(t *testing.T, size int) []*server.Server {
+	t.Helper()
+
+	// Client ports are chosen up front too, so a server cannot take another's route port
+	ports := freePorts(t, 2*size)
+	routes := make([]string, size)
+	for i := range routes {
+		routes[i] = fmt.Sprintf("nats://127.0.0.1:%d", ports[size+i])
+	}
+
+	servers := make([]*server.Server, size)
+	for i := range servers {
+		srv, err := server.NewServer(&server.Options{
+			ServerName: fmt.Sprintf("node-%d", i),
+			Host:       "127.0.0.1",
+			Port:       ports[i],
+			JetStream:  true,
+			StoreDir:   t.TempDir(),
+			NoLog:      true,
+			NoSigs:     true,
+			Cluster:    server.ClusterOpts{Name: "rimnats", Host: "127.0.0.1", Port: ports[size+i]},
+			Routes:     server.RoutesFromStr(strings.Join(append(append([]string{}, routes[:i]...), routes[i+1:]...), ",")),
+		})
+		if err != nil {
+			t.Fatalf("create server %d: %v", i, err)
+		}
+
+		go srv.Start()
+		t.Cleanup(func() {
+			srv.Shutdown()
+			srv.WaitForShutdown()
+		})
+
+		servers[i] = srv
+	}
+
+	for i, srv := range servers {
+		if !srv.ReadyForConnections(10 * time.Second) {
+			t.Fatalf("server %d not ready for connections", i)
+		}
+	}
+
+	return servers
+}
+
+func TestClusterFailoverKeepsJetStream(t *testing.T) {
+	servers := startCluster(t, 3)
+	urls := make([]string, len(servers))
+	for i, srv := range servers {
+		urls[i] = srv.ClientURL()
+	}
+
+	client := NewCluster(urls, WithLogger(&testLogger{}), WithNatsOptions(nats.ReconnectWait(50*time.Millisecond))).(*rimNats)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	// The stream is replicated so it survives the loss of a node
+	config := jetstream.StreamConfig{Name: "products", Subjects: []string{"product.>"}, Replicas: 3}
+	eventually(t, 20*time.Second, func() bool {
+		_, err := client.CreateStream(context.Background(), config)
+		return err == nil
+	}, "cluster did not create the stream")
+
+	js := client.JetStream()
+
+	received := make(chan string, 10)
+	_, err := client.Subscribe(context.Background(), "product.created", "products", "cluster_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			received <- msg.(*v1.ProductCreated).GetId()
+			return m.Ack()
+		}, WithConsumeOpts(jetstream.PullExpiry(time.Second))) // Short pulls so one lost with the node is soon replaced
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	publishAndReceive := func(id string) {
+		t.Helper()
+
+		eventually(t, 20*time.Second, func() bool {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			_, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: id})
+			return err == nil
+		}, "publish %s did not succeed", id)
+
+		timeout := time.After(20 * time.Second)
+		for {
+			select {
+			case got := <-received:
+				if got == id {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("message %s was not received", id)
+			}
+		}
+	}
+
+	publishAndReceive("before")
+
+	// Stop the node the client is connected to
+	connected := client.conn.ConnectedUrl()
+	for _, srv := range servers {
+		if srv.ClientURL() == connected {
+			srv.Shutdown()
+		}
+	}
+	eventually(t, 10*time.Second, func() bool {
+		return client.conn.IsConnected() && client.conn.ConnectedUrl() != connected
+	}, "client did not fail over from %s", connected)
+
+	publishAndReceive("after")
+
+	if client.JetStream() != js {
+		t.Error("JetStream context was replaced on failover")
+	}
+}
+
+func TestMaxReconnectsFromEnvironment(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", 10},
+		{"3", 3},
+		{"-1", -1},
+		{"often", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("RIMNATS.MAX_RECONNECTS", tt.value)
+
+			if got := getConfig().MaxRecon; got != tt.want {
+				t.Errorf("MaxRecon = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxReconnectsAppliedToConnection(t *testing.T) {
+	t.Setenv("RIMNATS.MAX_RECONNECTS", "4")
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	if got := client.conn.Opts.MaxReconnect; got != 4 {
+		t.Errorf("connection max reconnects = %d, want 4", got)
+	}
+}
+
+func TestConnectionName(t *testing.T) {
+	url := rimnatstest.StartServer(t)
+
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("RIMNATS.CLIENT", "")
+		os.Unsetenv("RIMNATS.CLIENT")
+
+		client := newTestClient(t, url)
+		if got := client.conn.Opts.Name; got != "Rimnats" {
+			t.Errorf("connection name = %q, want Rimnats", got)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("RIMNATS.CLIENT", "orders")
+
+		client := newTestClient(t, url)
+		if got := client.conn.Opts.Name; got != "orders" {
+			t.Errorf("connection name = %q, want orders", got)
+		}
+	})
+
+	t.Run("nats option", func(t *testing.T) {
+		t.Setenv("RIMNATS.CLIENT", "orders")
+
+		client := newTestClient(t, url, WithNatsOptions(nats.Name("billing")))
+		if got := client.conn.Opts.Name; got != "billing" {
+			t.Errorf("connection name = %q, want billing", got)
+		}
+	})
+}
+
+func TestCreateStreamConcurrently(t *testing.T) {
+	url := rimnatstest.StartServer(t)
+	config := jetstream.StreamConfig{Name: "products", Subjects: []string{"product.>"}}
+
+	// Every replica starts with its own client
+	const replicas = 8
+	clients := make([]*rimNats, replicas)
+	for i := range clients {
+		clients[i] = newTestClient(t, url)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, replicas)
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.CreateStream(context.Background(), config); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("create stream: %v", err)
+	}
+
+	names := clients[0].JetStream().StreamNames(context.Background())
+	count := 0
+	for range names.Name() {
+		count++
+	}
+	if count != 1 {
+		t.Errorf("server has %d streams, want 1", count)
+	}
+}
+
+func TestDrainStreamConsumers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "orders", "order.>")
+	createTestStream(t, client, "products", "product.>")
+
+	var mu sync.Mutex
+	received := map[string]int{}
+	subscribe := func(subject, stream, durable string) {
+		_, err := client.Subscribe(ctx, subject, stream, durable, func() proto.Message { return &v1.ProductCreated{} },
+			func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+				mu.Lock()
+				received[durable]++
+				mu.Unlock()
+				return m.Ack()
+			})
+		if err != nil {
+			t.Fatalf("subscribe %s: %v", durable, err)
+		}
+	}
+	subscribe("order.created", "orders", "orders_a")
+	subscribe("order.paid", "orders", "orders_b")
+	subscribe("product.created", "products", "products_a")
+
+	if err := client.DrainStreamConsumers(ctx, "orders"); err != nil {
+		t.Fatalf("drain stream consumers: %v", err)
+	}
+
+	for _, subject := range []string{"order.created", "order.paid", "product.created"} {
+		if _, err := client.Publish(ctx, subject, &v1.ProductCreated{}); err != nil {
+			t.Fatalf("publish %s: %v", subject, err)
+		}
+	}
+
+	eventually(t, 5*time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return received["products_a"] == 1
+	}, "products consumer stopped receiving")
+
+	// Give the drained consumers a chance to receive what they should not
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if received["orders_a"] != 0 || received["orders_b"] != 0 {
+		t.Errorf("drained consumers received %v", received)
+	}
+
+	for _, s := range client.subs.list() {
+		if s.Stream() == "orders" {
+			t.Errorf("subscription %s on orders is still tracked", s.Consumer())
+		}
+	}
+}
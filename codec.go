@@ -0,0 +1,75 @@
+package rimnats
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec defines how protobuf messages are turned into NATS payloads and back.
+// Implementations must be safe for concurrent use.
+type Codec interface {
+	// Marshal encodes the message into bytes suitable for a NATS payload
+	Marshal(msg proto.Message) ([]byte, error)
+	// Unmarshal decodes the payload into the provided message
+	Unmarshal(data []byte, msg proto.Message) error
+	// ContentType returns the MIME type describing the encoded payload
+	ContentType() string
+}
+
+// ProtoCodec encodes messages using the protobuf binary wire format.
+// It is the default codec for both events and RPC.
+type ProtoCodec struct{}
+
+// Marshal encodes the message using proto.Marshal.
+func (ProtoCodec) Marshal(msg proto.Message) ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+// Unmarshal decodes the payload using proto.Unmarshal.
+func (ProtoCodec) Unmarshal(data []byte, msg proto.Message) error {
+	return proto.Unmarshal(data, msg)
+}
+
+// ContentType returns "application/protobuf".
+func (ProtoCodec) ContentType() string {
+	return "application/protobuf"
+}
+
+// JSONCodec encodes messages using the canonical protobuf JSON mapping.
+// It is useful when exchanging messages with non-Go consumers.
+type JSONCodec struct {
+	MarshalOptions   protojson.MarshalOptions   // Options used when encoding messages
+	UnmarshalOptions protojson.UnmarshalOptions // Options used when decoding messages
+}
+
+// Marshal encodes the message using protojson.
+func (c JSONCodec) Marshal(msg proto.Message) ([]byte, error) {
+	return c.MarshalOptions.Marshal(msg)
+}
+
+// Unmarshal decodes the payload using protojson.
+func (c JSONCodec) Unmarshal(data []byte, msg proto.Message) error {
+	return c.UnmarshalOptions.Unmarshal(data, msg)
+}
+
+// ContentType returns "application/json".
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+// codecFor returns the codec for payloads labelled with contentType. Payloads without a
+// content type, or with one rimnats does not know, are decoded with fallback.
+func codecFor(fallback Codec, contentType string) Codec {
+	if contentType == "" || contentType == fallback.ContentType() {
+		return fallback
+	}
+
+	switch contentType {
+	case ProtoCodec{}.ContentType():
+		return ProtoCodec{}
+	case JSONCodec{}.ContentType():
+		return JSONCodec{}
+	default:
+		return fallback
+	}
+}
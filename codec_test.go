@@ -0,0 +1,83 @@
+package rimnats
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEventAndRPCCodecs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t), WithEventCodec(ProtoCodec{}), WithRPCCodec(JSONCodec{}))
+	createTestStream(t, client, "products", "product.>")
+
+	received := make(chan jetstream.Msg, 1)
+	_, err := client.Subscribe(ctx, "product.created", "products", "codec_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			if got := msg.(*v1.ProductCreated).GetName(); got != "chair" {
+				t.Errorf("event name = %q, want chair", got)
+			}
+			received <- m
+			return m.Ack()
+		})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Name: "chair"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case m := <-received:
+		if got := m.Headers().Get(HeaderContentType); got != "application/protobuf" {
+			t.Errorf("event content type = %q, want application/protobuf", got)
+		}
+		if json.Valid(m.Data()) {
+			t.Errorf("event payload %q is JSON, want protobuf", m.Data())
+		}
+	case <-ctx.Done():
+		t.Fatal("event not received")
+	}
+
+	// Observe the request on the wire alongside the responder
+	wire, err := client.conn.SubscribeSync("greeter.hello")
+	if err != nil {
+		t.Fatalf("subscribe sync: %v", err)
+	}
+
+	err = client.Reply("greeter.hello", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return &v1.SayHelloResponse{Message: "hello " + req.(*v1.SayHelloRequest).GetName()}, nil
+		})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	resp, err := client.Request(ctx, "greeter.hello", &v1.SayHelloRequest{Name: "ada"}, func() proto.Message { return &v1.SayHelloResponse{} }, 2*time.Second)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if got := resp.(*v1.SayHelloResponse).GetMessage(); got != "hello ada" {
+		t.Errorf("response = %q, want hello ada", got)
+	}
+
+	req, err := wire.NextMsg(time.Second)
+	if err != nil {
+		t.Fatalf("next request: %v", err)
+	}
+	if got := req.Header.Get(HeaderContentType); got != "application/json" {
+		t.Errorf("request content type = %q, want application/json", got)
+	}
+	if !json.Valid(req.Data) {
+		t.Errorf("request payload %q is not JSON", req.Data)
+	}
+}
@@ -0,0 +1,233 @@
+package rimnats
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Config is the structured configuration for a Rimnats client. It can be populated
+// from YAML, environment variables or a config library such as viper or koanf and
+// passed to NewFromConfig or NewWithConfig. Non-zero fields override the defaults taken
+// from the RIMNATS.* environment variables; zero fields keep them.
+type Config struct {
+	URL             string        `yaml:"url" mapstructure:"url"`                           // URL of the NATS server, may be a comma-separated list
+	Servers         []string      `yaml:"servers" mapstructure:"servers"`                   // Cluster members, used when URL is empty
+	ClientName      string        `yaml:"client_name" mapstructure:"client_name"`           // Name of the client used for connection identification
+	Debug           bool          `yaml:"debug" mapstructure:"debug"`                       // Enable debug mode for verbose logging
+	MaxReconnects   int           `yaml:"max_reconnects" mapstructure:"max_reconnects"`     // Maximum number of reconnection attempts, -1 for unlimited
+	ReconnectWait   time.Duration `yaml:"reconnect_wait" mapstructure:"reconnect_wait"`     // Time to wait between reconnection attempts
+	CredsFile       string        `yaml:"creds_file" mapstructure:"creds_file"`             // Path to a NATS credentials (.creds) file
+	Token           string        `yaml:"token" mapstructure:"token"`                       // Token used for token authentication
+	User            string        `yaml:"user" mapstructure:"user"`                         // User name used for user/password authentication
+	Password        string        `yaml:"password" mapstructure:"password"`                 // Password used for user/password authentication
+	NKeySeedFile    string        `yaml:"nkey_seed_file" mapstructure:"nkey_seed_file"`     // Path to an NKey seed file
+	TLSCertFile     string        `yaml:"tls_cert_file" mapstructure:"tls_cert_file"`       // Client certificate for mutual TLS
+	TLSKeyFile      string        `yaml:"tls_key_file" mapstructure:"tls_key_file"`         // Client key for mutual TLS
+	TLSCAFile       string        `yaml:"tls_ca_file" mapstructure:"tls_ca_file"`           // CA bundle used to verify the server
+	TLSConfig       *tls.Config   `yaml:"-" mapstructure:"-"`                               // Complete TLS configuration, takes precedence over the TLS files
+	JetStreamDomain string        `yaml:"jetstream_domain" mapstructure:"jetstream_domain"` // JetStream domain for leaf-node deployments
+	InboxPrefix     string        `yaml:"inbox_prefix" mapstructure:"inbox_prefix"`         // Prefix of request reply inboxes, e.g. a tenant-scoped one
+	OutboxSize      int           `yaml:"outbox_size" mapstructure:"outbox_size"`           // Maximum number of publishes buffered while disconnected
+	OutboxTTL       time.Duration `yaml:"outbox_ttl" mapstructure:"outbox_ttl"`             // Maximum age of a buffered publish
+	EventCodec      Codec         `yaml:"-" mapstructure:"-"`                               // Codec for Publish and Subscribe, defaults to ProtoCodec
+	RPCCodec        Codec         `yaml:"-" mapstructure:"-"`                               // Codec for Request and Reply, defaults to ProtoCodec
+	Metrics         *Metrics      `yaml:"-" mapstructure:"-"`                               // Prometheus metrics updated by the client
+	Logger          Logger        `yaml:"-" mapstructure:"-"`                               // Logger used by the client, defaults to a Beego console logger
+	NatsOptions     []nats.Option `yaml:"-" mapstructure:"-"`                               // Additional raw NATS connection options
+}
+
+// NewFromConfig creates a new Rimnats instance from a structured configuration.
+// It validates the configuration up front and returns an error when it is incomplete
+// or inconsistent. Call Connect on the returned client to establish the connection.
+func NewFromConfig(config Config) (Client, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(config), nil
+}
+
+// NewWithConfig creates a new Rimnats instance from a structured configuration.
+// The RIMNATS.* environment variables still provide the defaults; every non-zero field
+// of config overrides them while zero fields keep them, so Debug can only enable debug
+// mode. opts are applied last. Unlike NewFromConfig the configuration is not validated,
+// so mistakes surface when calling Connect.
+func NewWithConfig(config Config, opts ...Option) Client {
+	url := config.URL
+	if url == "" {
+		url = strings.Join(config.Servers, ",")
+	}
+
+	var natsOpts []nats.Option
+	if config.ClientName != "" {
+		natsOpts = append(natsOpts, nats.Name(config.ClientName))
+	}
+
+	if config.MaxReconnects != 0 {
+		natsOpts = append(natsOpts, nats.MaxReconnects(config.MaxReconnects))
+	}
+
+	if config.ReconnectWait > 0 {
+		natsOpts = append(natsOpts, nats.ReconnectWait(config.ReconnectWait))
+	}
+
+	configOpts := []Option{
+		WithNatsOptions(append(natsOpts, config.NatsOptions...)...),
+		config.apply,
+	}
+
+	return New(url, append(configOpts, opts...)...)
+}
+
+// apply copies the non-zero fields of c onto cfg, leaving the defaults of the zero ones in place.
+func (c Config) apply(cfg *nexorConfig) {
+	if c.ClientName != "" {
+		cfg.ClientName = c.ClientName
+	}
+
+	if c.Debug {
+		cfg.Debug = true
+	}
+
+	if c.MaxReconnects != 0 {
+		cfg.MaxRecon = c.MaxReconnects
+	}
+
+	if c.ReconnectWait > 0 {
+		cfg.ReconWait = int(c.ReconnectWait / time.Second)
+	}
+
+	if c.CredsFile != "" {
+		cfg.CredsFile = c.CredsFile
+	}
+
+	if c.Token != "" {
+		cfg.Token = c.Token
+	}
+
+	if c.User != "" {
+		cfg.User = c.User
+	}
+
+	if c.Password != "" {
+		cfg.Password = c.Password
+	}
+
+	if c.NKeySeedFile != "" {
+		cfg.NKeySeedFile = c.NKeySeedFile
+	}
+
+	if c.TLSCertFile != "" {
+		cfg.TLSCertFile = c.TLSCertFile
+	}
+
+	if c.TLSKeyFile != "" {
+		cfg.TLSKeyFile = c.TLSKeyFile
+	}
+
+	if c.TLSCAFile != "" {
+		cfg.TLSCAFile = c.TLSCAFile
+	}
+
+	if c.TLSConfig != nil {
+		cfg.TLSConfig = c.TLSConfig
+	}
+
+	if c.JetStreamDomain != "" {
+		cfg.Domain = c.JetStreamDomain
+	}
+
+	if c.InboxPrefix != "" {
+		cfg.InboxPrefix = c.InboxPrefix
+	}
+
+	if c.OutboxSize != 0 {
+		cfg.OutboxSize = c.OutboxSize
+	}
+
+	if c.OutboxTTL != 0 {
+		cfg.OutboxTTL = c.OutboxTTL
+	}
+
+	if c.EventCodec != nil {
+		cfg.EventCodec = c.EventCodec
+	}
+
+	if c.RPCCodec != nil {
+		cfg.RPCCodec = c.RPCCodec
+	}
+
+	if c.Metrics != nil {
+		cfg.Metrics = c.Metrics
+	}
+
+	if c.Logger != nil {
+		cfg.Logger = c.Logger
+	}
+}
+
+// validate reports configuration errors that would otherwise only surface on Connect.
+func (c Config) validate() error {
+	if c.URL == "" && len(c.Servers) == 0 {
+		return errors.New("rimnats: config requires a URL or at least one server")
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("rimnats: config requires both a TLS certificate and key file")
+	}
+
+	if c.Token != "" && c.User != "" {
+		return errors.New("rimnats: config cannot combine token and user/password authentication")
+	}
+
+	return nil
+}
+
+// connectOptions returns the NATS options used to connect, including the
+// authentication and TLS settings derived from the configuration.
+func (cfg *nexorConfig) connectOptions() ([]nats.Option, error) {
+	opts := append([]nats.Option(nil), cfg.Opts...)
+
+	if cfg.CredsFile != "" {
+		opts = append(opts, nats.UserCredentials(cfg.CredsFile))
+	}
+
+	if cfg.Token != "" {
+		opts = append(opts, nats.Token(cfg.Token))
+	}
+
+	if cfg.User != "" {
+		opts = append(opts, nats.UserInfo(cfg.User, cfg.Password))
+	}
+
+	if cfg.NKeySeedFile != "" {
+		opt, err := nats.NkeyOptionFromSeed(cfg.NKeySeedFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, opt)
+	}
+
+	if cfg.InboxPrefix != "" {
+		opts = append(opts, nats.CustomInboxPrefix(cfg.InboxPrefix))
+	}
+
+	if cfg.TLSConfig != nil {
+		opts = append(opts, nats.Secure(cfg.TLSConfig))
+	} else {
+		if cfg.TLSCertFile != "" {
+			opts = append(opts, nats.ClientCert(cfg.TLSCertFile, cfg.TLSKeyFile))
+		}
+
+		if cfg.TLSCAFile != "" {
+			opts = append(opts, nats.RootCAs(cfg.TLSCAFile))
+		}
+	}
+
+	return opts, nil
+}
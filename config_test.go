@@ -0,0 +1,114 @@
+package rimnats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	logger := &testLogger{}
+	metrics := NewMetrics()
+
+	client, err := NewFromConfig(Config{
+		URL:             rimnatstest.StartServer(t),
+		ClientName:      "config-test",
+		Debug:           true,
+		MaxReconnects:   7,
+		ReconnectWait:   3 * time.Second,
+		User:            "svc",
+		Password:        "secret",
+		JetStreamDomain: "hub",
+		InboxPrefix:     "_INBOX.tenant",
+		OutboxSize:      5,
+		OutboxTTL:       time.Minute,
+		EventCodec:      JSONCodec{},
+		RPCCodec:        JSONCodec{},
+		Metrics:         metrics,
+		Logger:          logger,
+	})
+	if err != nil {
+		t.Fatalf("new from config: %v", err)
+	}
+	if err := client.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	n := client.(*rimNats)
+	opts := n.conn.Opts
+	if opts.Name != "config-test" {
+		t.Errorf("connection name = %q, want config-test", opts.Name)
+	}
+	if opts.MaxReconnect != 7 {
+		t.Errorf("max reconnects = %d, want 7", opts.MaxReconnect)
+	}
+	if opts.ReconnectWait != 3*time.Second {
+		t.Errorf("reconnect wait = %v, want 3s", opts.ReconnectWait)
+	}
+	if opts.User != "svc" || opts.Password != "secret" {
+		t.Errorf("user info = %q/%q, want svc/secret", opts.User, opts.Password)
+	}
+	if opts.InboxPrefix != "_INBOX.tenant" {
+		t.Errorf("inbox prefix = %q, want _INBOX.tenant", opts.InboxPrefix)
+	}
+
+	if !n.cfg.Debug {
+		t.Error("debug mode not enabled")
+	}
+	if n.cfg.Domain != "hub" {
+		t.Errorf("jetstream domain = %q, want hub", n.cfg.Domain)
+	}
+	if n.outbox == nil || n.outbox.size != 5 || n.outbox.ttl != time.Minute {
+		t.Errorf("outbox = %+v, want size 5 and ttl 1m", n.outbox)
+	}
+	if _, ok := n.cfg.EventCodec.(JSONCodec); !ok {
+		t.Errorf("event codec = %T, want JSONCodec", n.cfg.EventCodec)
+	}
+	if _, ok := n.cfg.RPCCodec.(JSONCodec); !ok {
+		t.Errorf("rpc codec = %T, want JSONCodec", n.cfg.RPCCodec)
+	}
+	if n.cfg.Metrics != metrics {
+		t.Error("metrics not used")
+	}
+	if n.loggR != logger {
+		t.Error("logger not used")
+	}
+}
+
+func TestNewFromConfigValidates(t *testing.T) {
+	tests := map[string]Config{
+		"no url":           {},
+		"cert without key": {URL: "nats://localhost:4222", TLSCertFile: "client.pem"},
+		"token and user":   {URL: "nats://localhost:4222", Token: "t", User: "u"},
+	}
+
+	for name, config := range tests {
+		if _, err := NewFromConfig(config); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
+
+func TestNewWithConfigKeepsDefaultsOfZeroFields(t *testing.T) {
+	t.Setenv("RIMNATS.DEBUG", "true")
+	t.Setenv("RIMNATS.CLIENT", "env-client")
+	t.Setenv("RIMNATS.CREDS_FILE", "env.creds")
+
+	n := NewWithConfig(Config{URL: "nats://localhost:4222", Token: "secret"}).(*rimNats)
+	if !n.cfg.Debug || n.cfg.ClientName != "env-client" || n.cfg.CredsFile != "env.creds" {
+		t.Errorf("config = %+v, want the environment's debug mode, client name and creds file", n.cfg)
+	}
+	if n.cfg.Token != "secret" {
+		t.Errorf("token = %q, want secret", n.cfg.Token)
+	}
+	if _, ok := n.cfg.EventCodec.(ProtoCodec); !ok {
+		t.Errorf("event codec = %T, want the default ProtoCodec", n.cfg.EventCodec)
+	}
+
+	n = NewWithConfig(Config{URL: "nats://localhost:4222", ClientName: "config-client", CredsFile: "config.creds"}).(*rimNats)
+	if n.cfg.ClientName != "config-client" || n.cfg.CredsFile != "config.creds" {
+		t.Errorf("config = %+v, want the configured client name and creds file", n.cfg)
+	}
+}
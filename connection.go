@@ -0,0 +1,113 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// connectionHooks holds the callbacks registered for connection state changes.
+type connectionHooks struct {
+	mu           sync.RWMutex
+	onDisconnect []func(*nats.Conn, error)
+	onReconnect  []func(*nats.Conn)
+	onClosed     []func(*nats.Conn)
+}
+
+// options returns the NATS handlers that dispatch connection events to the registered callbacks.
+func (h *connectionHooks) options() []nats.Option {
+	return []nats.Option{
+		nats.DisconnectErrHandler(func(conn *nats.Conn, err error) {
+			h.mu.RLock()
+			defer h.mu.RUnlock()
+
+			for _, fn := range h.onDisconnect {
+				fn(conn, err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			h.mu.RLock()
+			defer h.mu.RUnlock()
+
+			for _, fn := range h.onReconnect {
+				fn(conn)
+			}
+		}),
+		nats.ClosedHandler(func(conn *nats.Conn) {
+			h.mu.RLock()
+			defer h.mu.RUnlock()
+
+			for _, fn := range h.onClosed {
+				fn(conn)
+			}
+		}),
+	}
+}
+
+// Status returns the current state of the NATS connection.
+func (n *rimNats) Status() nats.Status {
+	if n.conn == nil {
+		return nats.DISCONNECTED
+	}
+
+	return n.conn.Status()
+}
+
+// OnDisconnect registers fn to be called whenever the connection to the server is lost.
+// The error describes why the connection dropped and may be nil.
+func (n *rimNats) OnDisconnect(fn func(*nats.Conn, error)) {
+	n.hooks.mu.Lock()
+	defer n.hooks.mu.Unlock()
+
+	n.hooks.onDisconnect = append(n.hooks.onDisconnect, fn)
+}
+
+// OnReconnect registers fn to be called whenever the connection is re-established.
+func (n *rimNats) OnReconnect(fn func(*nats.Conn)) {
+	n.hooks.mu.Lock()
+	defer n.hooks.mu.Unlock()
+
+	n.hooks.onReconnect = append(n.hooks.onReconnect, fn)
+}
+
+// OnClosed registers fn to be called once the connection is permanently closed.
+func (n *rimNats) OnClosed(fn func(*nats.Conn)) {
+	n.hooks.mu.Lock()
+	defer n.hooks.mu.Unlock()
+
+	n.hooks.onClosed = append(n.hooks.onClosed, fn)
+}
+
+// waitForConnection blocks until the connection is established, maxWait elapses or ctx is done.
+// It reports whether the connection is up. Before Connect there is no connection to wait for.
+func (n *rimNats) waitForConnection(ctx context.Context, maxWait time.Duration) bool {
+	if n.conn == nil {
+		return false
+	}
+
+	if n.conn.IsConnected() {
+		return true
+	}
+
+	changes := n.conn.StatusChanged(nats.CONNECTED)
+	defer n.conn.RemoveStatusListener(changes)
+
+	// The connection may have recovered before the listener was registered
+	if n.conn.IsConnected() {
+		return true
+	}
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case <-changes:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	return n.conn.IsConnected()
+}
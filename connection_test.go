@@ -0,0 +1,45 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+)
+
+func TestPublishWaitsForConnection(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t), WithPublishWaitForConnection(5*time.Second))
+	createTestStream(t, client, "products", "product.>")
+
+	if err := client.conn.ForceReconnect(); err != nil {
+		t.Fatalf("force reconnect: %v", err)
+	}
+	if status := client.Status(); status != nats.RECONNECTING {
+		t.Fatalf("status = %v, want RECONNECTING", status)
+	}
+
+	ack, err := client.Publish(context.Background(), "product.created", &v1.ProductCreated{Id: "1"})
+	if err != nil {
+		t.Fatalf("publish while reconnecting: %v", err)
+	}
+	if ack == nil || ack.Stream != "products" {
+		t.Fatalf("ack = %+v, want an ack from the products stream", ack)
+	}
+}
+
+func TestPublishWaitBeforeConnect(t *testing.T) {
+	client := New(rimnatstest.StartServer(t), WithLogger(&testLogger{}), WithPublishWaitForConnection(5*time.Second))
+
+	start := time.Now()
+	_, err := client.Publish(context.Background(), "product.created", &v1.ProductCreated{Id: "1"})
+	if !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("publish before connect: got %v, want ErrDisconnected", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("publish before connect took %v, want it to fail without waiting", elapsed)
+	}
+}
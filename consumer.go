@@ -4,10 +4,17 @@
 package rimnats
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -25,23 +32,28 @@ type Subscriber interface {
 }
 
 // Subscribe sets up a subscription to a NATS subject with protobuf message handling.
-// It automatically decodes incoming messages using the provided protobuf message factory
-// and processes them with the specified handler.
+// It automatically decodes incoming messages with the configured event codec into instances
+// created by the provided protobuf message factory and processes them with the specified handler.
 //
 // Parameters:
 //   - subject: The NATS subject to subscribe to
 //   - stream: The stream name for the subscription (for JetStream persistence)
-//   - durable: The durable name for the subscription (for JetStream persistence)
+//   - durable: The durable name for the subscription (for JetStream persistence), empty for an ephemeral consumer
 //   - factory: A function that creates new instances of the protobuf message type
 //   - handler: A function that processes decoded protobuf messages
-//   - opts: Optional subscription options that override the default settings
+//   - opts: Optional subscription options such as WithConsumeOpts or WithUnackedAgeAlert
+//
+// The subscription runs until ctx is cancelled or it is stopped with Subscription.Stop.
 //
 // Default behavior:
-//   - Uses durable subscriptions for message persistence
-//   - Requires manual message acknowledgment
-//   - Sets a 30-second acknowledgment timeout
+//   - Uses durable subscriptions for message persistence. With an empty durable an ephemeral
+//     consumer is created instead; the server removes it once it has been inactive for
+//     ephemeralInactiveThreshold, so it does not survive restarts and suits one-off tools
+//   - Requires manual message acknowledgment unless WithAutoAck is set
+//   - Sets a 30-second acknowledgment timeout, configurable with WithAckWait
 //
 // Returns:
+//   - *Subscription: Handle used to stop the subscription
 //   - error: Returns an error if the subscription setup fails
 func (n *rimNats) Subscribe(
 	ctx context.Context,
@@ -50,58 +62,411 @@ func (n *rimNats) Subscribe(
 	durable string,
 	factory func() proto.Message,
 	handler ProtoHandler,
-	opts ...jetstream.PullConsumeOpt,
-) error {
-	jetStream, err := n.js.Stream(ctx, stream)
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return n.subscribe(ctx, []string{subject}, stream, durable, factory, handler, opts...)
+}
+
+// SubscribeMulti works like Subscribe but filters a single consumer on several subjects,
+// e.g. "orders.created" and "orders.cancelled", using the consumer's FilterSubjects.
+// The subjects must not overlap. FilterSubjects requires NATS server 2.10 or newer.
+//
+// Parameters:
+//   - subjects: The NATS subjects to subscribe to
+//   - stream: The stream name for the subscription (for JetStream persistence)
+//   - durable: The durable name for the subscription (for JetStream persistence)
+//   - factory: A function that creates new instances of the protobuf message type
+//   - handler: A function that processes decoded protobuf messages
+//   - opts: Optional subscription options such as WithConsumeOpts or WithUnackedAgeAlert
+//
+// Returns:
+//   - *Subscription: Handle used to stop the subscription
+//   - error: Returns an error if the subscription setup fails
+func (n *rimNats) SubscribeMulti(
+	ctx context.Context,
+	subjects []string,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	handler ProtoHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	if len(subjects) == 0 {
+		return nil, wrapError("subscribe", stream, "", errors.New("no subjects"))
+	}
+
+	return n.subscribe(ctx, subjects, stream, durable, factory, handler, opts...)
+}
+
+// subscribe creates or updates the durable consumer filtered on subjects and starts consuming it.
+func (n *rimNats) subscribe(
+	ctx context.Context,
+	subjects []string,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	handler ProtoHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	subject := strings.Join(subjects, ",")
+	options := newSubscribeOptions(opts)
+
+	// Look the consumer up first to tell a new consumer from a reused one
+	previous := n.existingConsumer(ctx, stream, cmp.Or(durable, options.consumerName))
+
+	jetStream, consumer, err := n.createConsumer(ctx, "subscribe", subjects, stream, durable, options)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result := consumerResult(previous, consumer.CachedInfo())
+	if n.cfg.Debug {
+		info := consumer.CachedInfo()
+		n.loggR.Info("🧩 [ rimnats ]: consumer ready", "stream", stream, "consumer", info.Name, "result", result,
+			"ack_wait", info.Config.AckWait, "max_deliver", info.Config.MaxDeliver)
+	}
+
+	sub := &Subscription{subject: subject, stream: stream, result: result, info: consumer.CachedInfo()}
+	consume := func(m jetstream.Msg) {
+		seq := sub.startHandling(m)
+		n.handle(ctx, m, factory, handler, options)
+		sub.finishHandling(seq)
+	}
+
+	if options.concurrency > 1 {
+		workers := make(chan struct{}, options.concurrency)
+		consume = func(m jetstream.Msg) {
+			// Blocks delivery until a worker is free
+			workers <- struct{}{}
+			// Counted before the goroutine starts so Shutdown cannot miss a delivered message
+			n.inflight.Add(1)
+			seq := sub.startHandling(m)
+			go func() {
+				defer n.inflight.Done()
+				defer func() { <-workers }()
+				n.handle(ctx, m, factory, handler, options)
+				sub.finishHandling(seq)
+			}()
+		}
+	}
+
+	// Subscribe to the subject with the provided options
+	consumeCtx, err := consumer.Consume(consume, options.consumeOpts...)
+
+	if err != nil {
+		if n.cfg.Debug {
+			n.loggR.Info("❌ [ rimnats ]: failed to subscribe to subject", "subject", subject, "stream", stream, "error", err)
+		}
+		return nil, wrapError("subscribe", stream, subject, err)
+	}
+
+	name := consumer.CachedInfo().Name
+	sub.consumer, sub.consume = name, consumeCtx
+	sub.restore = func(ctx context.Context) error {
+		return n.restoreConsumer(ctx, sub, jetStream, subjects, durable, consume, options)
+	}
+	n.subs.add(sub)
+
+	// Cancelling ctx tears the subscription down
+	go func() {
+		closed := make(chan struct{})
+		go func() {
+			sub.awaitClosed()
+			close(closed)
+		}()
+
+		select {
+		case <-ctx.Done():
+			sub.Stop()
+		case <-closed:
+		}
+	}()
+
+	if options.unackedAge != nil {
+		go n.monitorUnackedAge(options.unackedAge, jetStream, consumer, consumeCtx, name, consumer.CachedInfo().Config.FilterSubject)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🚀 [ rimnats ]: successfully subscribed to subject", "subject", subject, "stream", stream, "consumer", name)
 	}
 
-	consumer, err := jetStream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+	return sub, nil
+}
+
+// createConsumer creates or updates the durable consumer filtered on subjects, returning it with its stream.
+// An empty durable creates an ephemeral consumer removed after ephemeralInactiveThreshold of inactivity.
+func (n *rimNats) createConsumer(
+	ctx context.Context,
+	op string,
+	subjects []string,
+	stream string,
+	durable string,
+	options *subscribeOptions,
+) (jetstream.Stream, jetstream.Consumer, error) {
+	subject := strings.Join(subjects, ",")
+
+	config := jetstream.ConsumerConfig{
 		Name:          durable,
 		Durable:       durable,
-		AckWait:       30 * time.Second,
-		FilterSubject: subject,
-	})
-	if err != nil {
-		n.loggR.Error("🚨 [ rimnats ]: failed to create consumer: %v", err)
-		return err
+		AckWait:       options.ackWait,
+		MaxDeliver:    options.maxDeliver,
+		MaxAckPending: options.maxAckPending,
+		DeliverPolicy: options.deliverPolicy,
+		OptStartSeq:   options.startSeq,
+		OptStartTime:  options.startTime,
 	}
 
-	// Subscribe to the subject with the provided options
-	_, err = consumer.Consume(func(m jetstream.Msg) {
-		// Create a new instance of the protobuf message
-		msg := factory()
-		if err := proto.Unmarshal(m.Data(), msg); err != nil {
-			if n.cfg.Debug {
-				n.loggR.Info("🚨 [ rimnats ]: failed to decode protobuf: %v", err)
+	if durable == "" {
+		config.InactiveThreshold = ephemeralInactiveThreshold
+	}
+
+	if options.consumerName != "" {
+		if durable != "" && options.consumerName != durable {
+			err := fmt.Errorf("%w: consumer name %q must match durable %q", ErrInvalidConsumerConfig, options.consumerName, durable)
+			return nil, nil, wrapError(op, stream, subject, err)
+		}
+		config.Name = options.consumerName
+	}
+
+	// The default "<subject>.dlq" of a message matching a ">" filter matches it as well
+	if options.deadLetterSubject != nil && *options.deadLetterSubject == "" {
+		for _, filter := range subjects {
+			if filter == ">" || strings.HasSuffix(filter, ".>") {
+				err := fmt.Errorf("%w: filter %q needs an explicit dead-letter subject", ErrInvalidConsumerConfig, filter)
+				return nil, nil, wrapError(op, stream, subject, err)
 			}
+		}
+	}
+
+	if len(subjects) == 1 {
+		config.FilterSubject = subjects[0]
+	} else {
+		config.FilterSubjects = subjects
+	}
+
+	jetStream, err := n.js.Stream(ctx, stream)
+	if err != nil {
+		return nil, nil, wrapError(op, stream, subject, err)
+	}
+
+	if err := checkWorkQueueFilters(ctx, jetStream, config); err != nil {
+		return nil, nil, wrapError(op, stream, subject, err)
+	}
+
+	consumer, err := jetStream.CreateOrUpdateConsumer(ctx, config)
+	if err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to create consumer", "stream", stream, "durable", durable, "error", err)
+		return nil, nil, wrapError(op, stream, subject, err)
+	}
+
+	return jetStream, consumer, nil
+}
+
+// existingConsumer returns the info of the consumer named name on stream, nil when the name is
+// empty or the consumer does not exist.
+func (n *rimNats) existingConsumer(ctx context.Context, stream, name string) *jetstream.ConsumerInfo {
+	if name == "" {
+		return nil
+	}
+
+	consumer, err := n.js.Consumer(ctx, stream, name)
+	if err != nil {
+		return nil
+	}
+
+	return consumer.CachedInfo()
+}
+
+// consumerResult compares the consumer info from before and after creating a consumer.
+func consumerResult(previous, current *jetstream.ConsumerInfo) ConsumerResult {
+	switch {
+	case previous == nil:
+		return ConsumerCreated
+	case reflect.DeepEqual(previous.Config, current.Config):
+		return ConsumerUnchanged
+	default:
+		return ConsumerUpdated
+	}
+}
+
+// ephemeralInactiveThreshold is how long an ephemeral consumer may go without being consumed
+// before the server removes it.
+const ephemeralInactiveThreshold = 5 * time.Minute
+
+// handle decodes a JetStream message and passes it to the handler, NAKing or dead-lettering
+// it when decoding or the handler fails.
+func (n *rimNats) handle(ctx context.Context, m jetstream.Msg, factory func() proto.Message, handler ProtoHandler, options *subscribeOptions) {
+	n.inflight.Add(1)
+	defer n.inflight.Done()
+
+	stream := messageStream(m)
+	if n.cfg.Metrics != nil {
+		m = &meteredMsg{Msg: m, metrics: n.cfg.Metrics, stream: stream}
+	}
 
-			_ = m.Nak() // NACK to let NATS know we couldn't process the message
+	// Every message gets its own context, cancelled once it has been handled
+	var cancel context.CancelFunc
+	switch {
+	case options.handlerTimeout > 0:
+		ctx, cancel = context.WithTimeout(ctx, options.handlerTimeout)
+	case options.handlerTimeout < 0:
+		ctx, cancel = context.WithTimeout(ctx, options.ackWait)
+	default:
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	ctx = n.propagator().Extract(ctx, headerCarrier(m.Headers()))
+	ctx, span := n.startSpan(ctx, "process", m.Subject(), trace.SpanKindConsumer, attribute.String(attrStream, stream))
+	defer span.End()
+
+	key := ""
+	if options.seen != nil {
+		key = seenKey(m)
+		if n.alreadySeen(ctx, m, key, options.seen) {
+			span.SetAttributes(attribute.String(attrAck, "duplicate"))
 			return
 		}
+	}
+
+	stopInProgress := func() {}
+	switch {
+	case options.inProgress > 0:
+		stopInProgress = n.startInProgress(m, options.inProgress)
+	case options.inProgress < 0 && options.ackWait > 0:
+		stopInProgress = n.startInProgress(m, options.ackWait/2)
+	}
+
+	start := time.Now()
+	err := n.process(ctx, m, factory, n.middleware.wrap(handler), options)
+	stopInProgress()
+	n.cfg.Metrics.observeConsumed(m.Subject(), stream, time.Since(start), err)
+	if err != nil {
+		recordSpanError(span, err)
+		span.SetAttributes(attribute.String(attrAck, n.fail(ctx, m, err, options)))
+		return
+	}
+
+	if key != "" {
+		if err := options.seen.MarkSeen(ctx, key); err != nil {
+			n.loggR.Error("❌ [ rimnats ]: failed to mark message as processed", "subject", m.Subject(), "key", key, "error", err)
+		}
+	}
+
+	if !options.autoAck {
+		span.SetAttributes(attribute.String(attrAck, "manual"))
+		return
+	}
+
+	if err := m.Ack(); err != nil {
+		recordSpanError(span, err)
+		if n.cfg.Debug {
+			n.loggR.Info("🚨 [ rimnats ]: failed to acknowledge message", "subject", m.Subject(), "error", err)
+		}
+		return
+	}
+
+	span.SetAttributes(attribute.String(attrAck, "ack"))
+}
 
-		// Call the handler to process the message
-		if err := handler(ctx, msg, m); err != nil {
+// alreadySeen reports whether the message identified by key was processed before, in which
+// case it is acknowledged without being handled again. Lookup failures are logged and the
+// message is handled.
+func (n *rimNats) alreadySeen(ctx context.Context, m jetstream.Msg, key string, store SeenStore) bool {
+	if key == "" {
+		return false
+	}
+
+	seen, err := store.Seen(ctx, key)
+	if err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to check processed messages", "subject", m.Subject(), "key", key, "error", err)
+		return false
+	}
+	if !seen {
+		return false
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("♻️ [ rimnats ]: skipping already processed message", "subject", m.Subject(), "key", key)
+	}
+
+	if err := m.Ack(); err != nil && n.cfg.Debug {
+		n.loggR.Info("🚨 [ rimnats ]: failed to acknowledge message", "subject", m.Subject(), "error", err)
+	}
+
+	return true
+}
+
+// process decodes a JetStream message and passes it to the handler.
+func (n *rimNats) process(ctx context.Context, m jetstream.Msg, factory func() proto.Message, handler ProtoHandler, options *subscribeOptions) error {
+	data := m.Data()
+	if options.decode != nil {
+		var err error
+		if data, err = options.decode(data); err != nil {
 			if n.cfg.Debug {
-				n.loggR.Info("🚨 [ rimnats ]: handler error: %v", err)
+				n.loggR.Info("🚨 [ rimnats ]: failed to transform message payload", "subject", m.Subject(), "error", err)
 			}
 
-			_ = m.Nak() // NACK if the handler fails
-			return
+			return err
 		}
-	}, opts...)
+	}
 
-	if err != nil {
+	codec := n.cfg.EventCodec
+	if options.codec != nil {
+		codec = options.codec
+	}
+
+	if options.factoryFor != nil {
+		var err error
+		if factory, err = options.factoryFor(m.Subject()); err != nil {
+			return err
+		}
+	}
+
+	// Create a new instance of the protobuf message
+	msg := factory()
+	if err := checkMessageType(m.Headers(), msg); err != nil {
 		if n.cfg.Debug {
-			n.loggR.Info("❌ [ rimnats ]: failed to subscribe to subject: %s: %v", subject, err)
+			n.loggR.Info("🚨 [ rimnats ]: unexpected message type", "subject", m.Subject(), "error", err)
 		}
+
 		return err
 	}
 
-	if n.cfg.Debug {
-		n.loggR.Info("🚀 [ rimnats ]: successfully subscribed to subject: %s", subject)
+	if err := codecFor(codec, m.Headers().Get(HeaderContentType)).Unmarshal(data, msg); err != nil {
+		if n.cfg.Debug {
+			n.loggR.Info("🚨 [ rimnats ]: failed to decode message", "subject", m.Subject(), "error", err)
+		}
+
+		return unmarshalError(err)
+	}
+
+	// Call the handler to process the message
+	if err := n.callHandler(ctx, handler, msg, m); err != nil {
+		if n.cfg.Debug {
+			n.loggR.Info("🚨 [ rimnats ]: handler error", "subject", m.Subject(), "error", err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// callHandler calls handler, turning a panic into an error so the message is NAKed and
+// the consumer keeps running.
+func (n *rimNats) callHandler(ctx context.Context, handler ProtoHandler, msg proto.Message, m jetstream.Msg) (err error) {
+	defer recoverPanic(n.loggR, m.Subject(), &err)
+
+	return handler(ctx, msg, m)
+}
+
+// messageStream returns the stream a JetStream message was delivered from, empty when unknown.
+func messageStream(m jetstream.Msg) string {
+	meta, err := m.Metadata()
+	if err != nil {
+		return ""
 	}
 
-	return err
+	return meta.Stream
 }
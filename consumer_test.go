@@ -0,0 +1,141 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSubscribeRecoversFromHandlerPanic(t *testing.T) {
+	logger := &testLogger{}
+	client := newTestClient(t, rimnatstest.StartServer(t), WithLogger(logger))
+	createTestStream(t, client, "products", "product.>")
+
+	var (
+		mu       sync.Mutex
+		panicked bool
+		handled  []string
+	)
+	_, err := client.Subscribe(context.Background(), "product.created", "products", "panic_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !panicked {
+				panicked = true
+				panic("boom")
+			}
+			handled = append(handled, msg.(*v1.ProductCreated).GetId())
+			return m.Ack()
+		})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	for _, id := range []string{"p-1", "p-2", "p-3"} {
+		if _, err := client.Publish(context.Background(), "product.created", &v1.ProductCreated{Id: id}); err != nil {
+			t.Fatalf("publish %s: %v", id, err)
+		}
+	}
+
+	// The panicking message is NAKed and redelivered, and the consumer keeps delivering
+	eventually(t, 10*time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 3
+	}, "not all three messages were handled")
+
+	if !logger.contains("ERROR", "handler panicked") {
+		t.Error("panic was not logged")
+	}
+}
+
+func TestReplyRecoversFromHandlerPanic(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	err := client.Reply("greeter.hello", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			if req.(*v1.SayHelloRequest).GetName() == "" {
+				panic("no name")
+			}
+			return &v1.SayHelloResponse{Message: "hello " + req.(*v1.SayHelloRequest).GetName()}, nil
+		})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	factory := func() proto.Message { return &v1.SayHelloResponse{} }
+
+	_, err = client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{}, factory, time.Second)
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) {
+		t.Fatalf("request: got %v, want a ServiceError", err)
+	}
+	if serviceErr.Code != "500" || !strings.Contains(serviceErr.Description, "panicked") {
+		t.Errorf("error = %+v, want a 500 reporting the panic", serviceErr)
+	}
+
+	// The responder keeps answering after the panic
+	resp, err := client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{Name: "ada"}, factory, time.Second)
+	if err != nil {
+		t.Fatalf("request after panic: %v", err)
+	}
+	if got := resp.(*v1.SayHelloResponse).GetMessage(); got != "hello ada" {
+		t.Errorf("message = %q, want hello ada", got)
+	}
+}
+
+func TestSubscribeDeadLettersUndecodableMessages(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "products", "product.>")
+
+	var (
+		mu     sync.Mutex
+		failed []error
+	)
+	sub, err := client.Subscribe(ctx, "product.created", "products", "dead_letter_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			t.Errorf("handler called with %v", msg)
+			return nil
+		}, WithDeadLetter(func(ctx context.Context, m jetstream.Msg, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, err)
+		}))
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	// A message of another type and a payload that is not protobuf at all
+	if _, err := client.Publish(ctx, "product.created", &v1.SayHelloRequest{Name: "ada"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if _, err := client.JetStream().Publish(ctx, "product.created", []byte{0xff}); err != nil {
+		t.Fatalf("publish raw: %v", err)
+	}
+
+	// Both are terminated on their first delivery instead of redelivered
+	consumer, err := client.JetStream().Consumer(ctx, "products", sub.Consumer())
+	if err != nil {
+		t.Fatalf("consumer: %v", err)
+	}
+	eventually(t, 5*time.Second, func() bool {
+		info, err := consumer.Info(ctx)
+		return err == nil && info.AckFloor.Stream == 2 && info.NumAckPending == 0 && info.NumRedelivered == 0
+	}, "messages were not terminated")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failed) != 2 || !errors.Is(failed[0], ErrMessageType) || !errors.Is(failed[1], ErrUnmarshal) {
+		t.Errorf("dead letters = %v, want ErrMessageType and ErrUnmarshal", failed)
+	}
+}
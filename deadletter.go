@@ -0,0 +1,127 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DeadLetterHandler is called with a message that exhausted its deliveries, or failed in a way
+// redelivery cannot fix, and the error returned by its last attempt.
+type DeadLetterHandler func(ctx context.Context, m jetstream.Msg, err error)
+
+// WithMaxDeliver limits how many times a message is delivered. When the last allowed attempt
+// fails the message is terminated instead of NAKed and passed to the dead-letter handler and
+// subject, if configured. Without it failing messages are redelivered forever, except those
+// failing with ErrNoHandler, ErrNoFactory, ErrMessageType or ErrUnmarshal, which are
+// dead-lettered and terminated on their first delivery.
+func WithMaxDeliver(max int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.maxDeliver = max
+	}
+}
+
+// WithDeadLetter calls fn with every message that exhausted the deliveries allowed by WithMaxDeliver
+// or failed in a way redelivery cannot fix.
+func WithDeadLetter(fn DeadLetterHandler) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deadLetter = fn
+	}
+}
+
+// WithDeadLetterSubject republishes messages that exhausted the deliveries allowed by WithMaxDeliver,
+// or failed in a way redelivery cannot fix, to subject, keeping their original headers and adding
+// HeaderDeliveryCount. The subject must be bound to a stream, since dead letters are published
+// through JetStream. An empty subject routes them to "<subject>.dlq", where subject is the one the
+// message was published to; it is rejected with ErrInvalidConsumerConfig for filters ending in ">",
+// which would consume their own dead letters.
+func WithDeadLetterSubject(subject string) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deadLetterSubject = &subject
+	}
+}
+
+// permanentErrors are failures that redelivering the message cannot fix.
+var permanentErrors = []error{ErrNoHandler, ErrNoFactory, ErrMessageType, ErrUnmarshal}
+
+// permanentFailure reports whether err is a failure that redelivering the message cannot fix,
+// returning the reason it is terminated with.
+func permanentFailure(err error) (string, bool) {
+	for _, target := range permanentErrors {
+		if errors.Is(err, target) {
+			return strings.TrimPrefix(target.Error(), "rimnats: "), true
+		}
+	}
+
+	return "", false
+}
+
+// fail settles a message whose decoding or handler failed. It is NAKed for redelivery unless
+// this was its last allowed delivery, in which case it is dead-lettered and terminated.
+// Messages that can never be processed, because no handler or factory matches them or they
+// cannot be decoded, are dead-lettered and terminated right away. It returns the
+// acknowledgment sent, "nak" or "term".
+func (n *rimNats) fail(ctx context.Context, m jetstream.Msg, err error, options *subscribeOptions) string {
+	var delivered uint64 = 1
+	meta, metaErr := m.Metadata()
+	if metaErr == nil {
+		delivered = meta.NumDelivered
+	}
+
+	if reason, ok := permanentFailure(err); ok {
+		n.loggR.Warn("💀 [ rimnats ]: terminating message that cannot be processed", "subject", m.Subject(), "error", err)
+		n.deadLetter(ctx, m, delivered, err, options)
+		_ = m.TermWithReason(reason)
+		return "term"
+	}
+
+	if options.maxDeliver > 0 && metaErr == nil && delivered >= uint64(options.maxDeliver) {
+		n.loggR.Warn("💀 [ rimnats ]: message exhausted its deliveries", "subject", m.Subject(), "deliveries", delivered, "error", err)
+		n.deadLetter(ctx, m, delivered, err, options)
+		_ = m.Term()
+		return "term"
+	}
+
+	_ = m.Nak() // NACK to let NATS redeliver the message
+	return "nak"
+}
+
+// deadLetter routes a message that is terminated after failing to the configured subject and handler.
+func (n *rimNats) deadLetter(ctx context.Context, m jetstream.Msg, delivered uint64, err error, options *subscribeOptions) {
+	if options.deadLetterSubject != nil {
+		n.publishDeadLetter(ctx, m, *options.deadLetterSubject, delivered)
+	}
+
+	if options.deadLetter != nil {
+		options.deadLetter(ctx, m, err)
+	}
+}
+
+// publishDeadLetter republishes m to subject through JetStream, or to "<subject>.dlq" when
+// subject is empty. Failures are logged, the message is terminated either way.
+func (n *rimNats) publishDeadLetter(ctx context.Context, m jetstream.Msg, subject string, delivered uint64) {
+	if subject == "" {
+		subject = m.Subject() + ".dlq"
+	}
+
+	if n.js == nil {
+		n.loggR.Error("❌ [ rimnats ]: dead letters need a JetStream connection", "subject", subject)
+		return
+	}
+
+	dlq := nats.NewMsg(subject)
+	dlq.Data = m.Data()
+	for key, values := range m.Headers() {
+		dlq.Header[key] = values
+	}
+	dlq.Header.Set(HeaderDeliveryCount, strconv.FormatUint(delivered, 10))
+
+	// The dead letter is published even when the handler context has been cancelled
+	if _, err := n.js.PublishMsg(context.WithoutCancel(ctx), dlq); err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to publish dead letter", "subject", subject, "error", err)
+	}
+}
@@ -0,0 +1,199 @@
+package rimnats
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DedupStore caches encoded responses for WithRequestDedup. Entries are keyed by the request
+// subject together with its request ID, so a store can be shared by responders on different
+// subjects. Implementations must be safe for concurrent use.
+type DedupStore interface {
+	// Get returns the response cached for id, if it has not expired.
+	Get(id string) ([]byte, bool)
+	// Set caches resp for id for the duration of ttl.
+	Set(id string, resp []byte, ttl time.Duration)
+}
+
+// memoryDedupEntry is a response cached by memoryDedupStore.
+type memoryDedupEntry struct {
+	resp      []byte    // Encoded response
+	expiresAt time.Time // Time after which the entry is ignored
+}
+
+// dedupExpiry records when the entry for id expires, ordering the eviction heaps of memoryDedupStore and MemorySeenStore.
+type dedupExpiry struct {
+	id        string
+	expiresAt time.Time
+}
+
+// dedupExpiries is a min-heap of expiries, the earliest first.
+type dedupExpiries []dedupExpiry
+
+func (h dedupExpiries) Len() int           { return len(h) }
+func (h dedupExpiries) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h dedupExpiries) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *dedupExpiries) Push(x any)        { *h = append(*h, x.(dedupExpiry)) }
+func (h *dedupExpiries) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// memoryDedupStore is an in-process DedupStore.
+type memoryDedupStore struct {
+	mu       sync.Mutex
+	entries  map[string]memoryDedupEntry
+	expiries dedupExpiries
+	now      func() time.Time
+}
+
+// NewMemoryDedupStore creates a DedupStore keeping responses in memory. Expired entries are
+// evicted in expiry order as new responses are cached, so it suits a single responder instance;
+// replicas behind ReplyQueue need a shared store to deduplicate retries answered by another instance.
+func NewMemoryDedupStore() DedupStore {
+	return &memoryDedupStore{entries: make(map[string]memoryDedupEntry), now: time.Now}
+}
+
+// Get returns the response cached for id, if it has not expired.
+func (s *memoryDedupStore) Get(id string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[id]
+	if !ok {
+		return nil, false
+	}
+
+	if s.now().After(entry.expiresAt) {
+		delete(s.entries, id)
+		return nil, false
+	}
+
+	return entry.resp, true
+}
+
+// Set caches resp for id for the duration of ttl, evicting the entries that have expired.
+func (s *memoryDedupStore) Set(id string, resp []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for len(s.expiries) > 0 && now.After(s.expiries[0].expiresAt) {
+		expired := heap.Pop(&s.expiries).(dedupExpiry)
+		// Skip expiries of entries that were evicted by Get or cached again since
+		if entry, ok := s.entries[expired.id]; ok && entry.expiresAt.Equal(expired.expiresAt) {
+			delete(s.entries, expired.id)
+		}
+	}
+
+	entry := memoryDedupEntry{resp: resp, expiresAt: now.Add(ttl)}
+	s.entries[id] = entry
+	heap.Push(&s.expiries, dedupExpiry{id: id, expiresAt: entry.expiresAt})
+}
+
+// dedupKey identifies the request with ID id sent to subject. Equal IDs sent to different
+// subjects belong to different requests.
+func dedupKey(subject, id string) string {
+	return subject + " " + id
+}
+
+// dedupFlight tracks the deduplicated requests being handled, so a duplicate arriving before
+// the first request has been answered waits for its response instead of running the handler
+// again. The zero value is ready to use.
+type dedupFlight struct {
+	mu    sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is a request being handled, identified by its subject and request ID.
+type dedupCall struct {
+	done chan struct{} // Closed once the request has been answered
+	resp []byte        // Encoded response, nil when the handler failed
+}
+
+// join returns the call handling id. The bool is true when the caller reserved the ID and
+// must handle the request and finish the call.
+func (f *dedupFlight) join(id string) (*dedupCall, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if call, ok := f.calls[id]; ok {
+		return call, false
+	}
+
+	if f.calls == nil {
+		f.calls = make(map[string]*dedupCall)
+	}
+
+	call := &dedupCall{done: make(chan struct{})}
+	f.calls[id] = call
+
+	return call, true
+}
+
+// finish releases id and wakes the duplicates waiting on call with resp.
+func (f *dedupFlight) finish(id string, call *dedupCall, resp []byte) {
+	f.mu.Lock()
+	delete(f.calls, id)
+	f.mu.Unlock()
+
+	call.resp = resp
+	close(call.done)
+}
+
+// reserveRequest reserves id, built by dedupKey, for the caller, which must then handle the
+// request and finish the returned call. When the ID has already been answered, or is answered while the
+// caller waits for the request holding it, the cached response is returned instead. Both are nil
+// when ctx ends first.
+func (n *rimNats) reserveRequest(ctx context.Context, store DedupStore, id string) (*dedupCall, []byte) {
+	for {
+		if cached, ok := store.Get(id); ok {
+			return nil, cached
+		}
+
+		call, first := n.requests.join(id)
+		if first {
+			// The previous holder may have cached its response between Get and join
+			if cached, ok := store.Get(id); ok {
+				n.requests.finish(id, call, cached)
+				return nil, cached
+			}
+
+			return call, nil
+		}
+
+		select {
+		case <-call.done:
+			if call.resp != nil {
+				return nil, call.resp
+			}
+			// The request holding the ID failed, so try to reserve it for this one
+		case <-ctx.Done():
+			return nil, nil
+		}
+	}
+}
+
+// WithRequestDedup caches successful responses in store for ttl, keyed by the request subject
+// and its HeaderRequestID header. A retried request carrying the same ID gets the cached response instead of running
+// the handler again, so side effects are not repeated. A retry arriving while the first request
+// is still being handled by the same client waits for its response. Requests without an ID and
+// failed responses are never cached.
+func WithRequestDedup(store DedupStore, ttl time.Duration) ReplyOption {
+	return func(o *replyOptions) {
+		o.dedup = store
+		o.dedupTTL = ttl
+	}
+}
+
+// WithRequestID sends id in the HeaderRequestID header. Reuse the same ID when retrying a
+// request so a responder using WithRequestDedup answers it only once.
+func WithRequestID(id string) RequestOption {
+	return func(o *requestOptions) {
+		o.requestID = id
+	}
+}
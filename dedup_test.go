@@ -0,0 +1,151 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequestDedup(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	var calls atomic.Int32
+	err := client.Reply("order.place", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			n := calls.Add(1)
+			return &v1.SayHelloResponse{Message: fmt.Sprintf("order %d", n)}, nil
+		}, WithRequestDedup(NewMemoryDedupStore(), time.Minute))
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	var responses []string
+	for range 2 {
+		resp, err := client.Request(context.Background(), "order.place", &v1.SayHelloRequest{}, func() proto.Message { return &v1.SayHelloResponse{} },
+			time.Second, WithRequestID("order-1"))
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		responses = append(responses, resp.(*v1.SayHelloResponse).GetMessage())
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+	if responses[0] != "order 1" || responses[1] != responses[0] {
+		t.Errorf("responses = %v, want the same response twice", responses)
+	}
+}
+
+func TestRequestDedupWaitsForInFlightRequest(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	store := NewMemoryDedupStore()
+
+	// Two responders in the same queue group handle requests concurrently
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		calls.Add(1)
+		<-release
+		return &v1.SayHelloResponse{Message: "placed"}, nil
+	}
+	for range 2 {
+		if err := client.ReplyQueue("order.place", "orders", func() proto.Message { return &v1.SayHelloRequest{} }, handler, WithRequestDedup(store, time.Minute)); err != nil {
+			t.Fatalf("reply queue: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]string, 4)
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Request(context.Background(), "order.place", &v1.SayHelloRequest{}, func() proto.Message { return &v1.SayHelloResponse{} },
+				5*time.Second, WithRequestID("order-1"))
+			if err != nil {
+				t.Errorf("request: %v", err)
+				return
+			}
+			responses[i] = resp.(*v1.SayHelloResponse).GetMessage()
+		}()
+	}
+
+	eventually(t, 5*time.Second, func() bool { return calls.Load() > 0 }, "handler not called")
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+	for i, resp := range responses {
+		if resp != "placed" {
+			t.Errorf("response %d = %q, want placed", i, resp)
+		}
+	}
+}
+
+func TestMemoryDedupStoreExpiry(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryDedupStore().(*memoryDedupStore)
+	store.now = func() time.Time { return now }
+
+	store.Set("a", []byte("a"), time.Minute)
+	store.Set("b", []byte("b"), time.Hour)
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.Get("a"); ok {
+		t.Error("expired entry returned")
+	}
+	if resp, ok := store.Get("b"); !ok || string(resp) != "b" {
+		t.Errorf("Get(b) = %q, %v, want b", resp, ok)
+	}
+
+	// Caching another response evicts the expired entries
+	store.Set("c", []byte("c"), time.Minute)
+	store.Set("a", []byte("a2"), time.Minute)
+	now = now.Add(2 * time.Minute)
+	store.Set("d", []byte("d"), time.Minute)
+
+	if len(store.entries) != 2 {
+		t.Errorf("store holds %d entries, want b and d", len(store.entries))
+	}
+	if resp, ok := store.Get("b"); !ok || string(resp) != "b" {
+		t.Errorf("Get(b) = %q, %v, want b", resp, ok)
+	}
+}
+
+func TestRequestDedupKeysBySubject(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	store := NewMemoryDedupStore()
+
+	for _, subject := range []string{"order.place", "order.cancel"} {
+		err := client.Reply(subject, func() proto.Message { return &v1.SayHelloRequest{} },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return &v1.SayHelloResponse{Message: subject}, nil
+			}, WithRequestDedup(store, time.Minute))
+		if err != nil {
+			t.Fatalf("reply %s: %v", subject, err)
+		}
+	}
+
+	// The same request ID sent to another subject is a different request
+	for _, subject := range []string{"order.place", "order.cancel"} {
+		resp, err := client.Request(context.Background(), subject, &v1.SayHelloRequest{}, func() proto.Message { return &v1.SayHelloResponse{} },
+			time.Second, WithRequestID("order-1"))
+		if err != nil {
+			t.Fatalf("request %s: %v", subject, err)
+		}
+		if got := resp.(*v1.SayHelloResponse).GetMessage(); got != subject {
+			t.Errorf("response to %s = %q, want %s", subject, got, subject)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package rimnats
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// WithDeliverPolicy sets where a new consumer starts in the stream, e.g. jetstream.DeliverNewPolicy
+// to only receive messages published after it is created. Consumers deliver all messages by
+// default. The policy of an existing durable consumer cannot be changed; delete it first.
+func WithDeliverPolicy(policy jetstream.DeliverPolicy) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deliverPolicy = policy
+	}
+}
+
+// WithStartSequence starts a new consumer at stream sequence seq, using jetstream.DeliverByStartSequencePolicy.
+func WithStartSequence(seq uint64) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deliverPolicy = jetstream.DeliverByStartSequencePolicy
+		o.startSeq = seq
+		o.startTime = nil
+	}
+}
+
+// WithStartTime starts a new consumer at the first message stored at or after t, using
+// jetstream.DeliverByStartTimePolicy. This is the usual way to replay events after a bug fix.
+func WithStartTime(t time.Time) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.deliverPolicy = jetstream.DeliverByStartTimePolicy
+		o.startTime = &t
+		o.startSeq = 0
+	}
+}
@@ -0,0 +1,170 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Sentinel errors returned by rimnats. They can be matched with errors.Is and are
+// returned alongside the original JetStream error, which also remains matchable.
+var (
+	ErrStreamNotFound        = errors.New("rimnats: stream not found")
+	ErrStreamExists          = errors.New("rimnats: stream already exists")
+	ErrConsumerNotFound      = errors.New("rimnats: consumer not found")
+	ErrConsumerExists        = errors.New("rimnats: consumer already exists")
+	ErrMsgNotFound           = errors.New("rimnats: message not found")
+	ErrJetStreamNotEnabled   = errors.New("rimnats: jetstream not enabled")
+	ErrOutboxFull            = errors.New("rimnats: outbox full")
+	ErrDisconnected          = errors.New("rimnats: not connected")
+	ErrNotConnected          = ErrDisconnected
+	ErrNoHandler             = errors.New("rimnats: no handler for message")
+	ErrNoFactory             = errors.New("rimnats: no factory registered for subject")
+	ErrJetStreamUnavailable  = errors.New("rimnats: jetstream unavailable")
+	ErrKeyNotFound           = errors.New("rimnats: key not found")
+	ErrPayloadTooLarge       = errors.New("rimnats: payload too large")
+	ErrHandlerPanic          = errors.New("rimnats: handler panicked")
+	ErrInvalidStreamConfig   = errors.New("rimnats: invalid stream config")
+	ErrWorkQueueOverlap      = errors.New("rimnats: work queue consumer filters overlap")
+	ErrInvalidConsumerConfig = errors.New("rimnats: invalid consumer config")
+	ErrTimeout               = errors.New("rimnats: timeout")
+	ErrNoResponders          = errors.New("rimnats: no responders")
+	ErrUnmarshal             = errors.New("rimnats: failed to decode payload")
+	ErrNotSupported          = errors.New("rimnats: not supported by the in-memory client")
+	ErrWrongSequence         = errors.New("rimnats: wrong last sequence")
+	ErrMessageType           = errors.New("rimnats: unexpected message type")
+	ErrRateLimited           = errors.New("rimnats: publish rate limit exceeded")
+)
+
+// ServiceError is returned by Request when the responder reported a failure. Reply handlers
+// may return a *ServiceError to choose the code sent to the requester; any other error is
+// reported with code "500" and its message as the description.
+type ServiceError struct {
+	Code        string // Error code, e.g. "400" or "500"
+	Description string // Human-readable description of the failure
+}
+
+// Error returns the error code and description.
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("rimnats: service error %s: %s", e.Code, e.Description)
+}
+
+// serviceError returns the failure reported in the headers of a response, nil when the
+// responder succeeded. Either service error header marks a failure, so an error with an
+// empty description is still reported.
+func serviceError(header nats.Header) *ServiceError {
+	code := header.Get(HeaderServiceErrorCode)
+	if code == "" && header.Values(HeaderServiceError) == nil {
+		return nil
+	}
+
+	return &ServiceError{Code: code, Description: header.Get(HeaderServiceError)}
+}
+
+// jetStreamErrors maps NATS and JetStream errors onto their rimnats sentinel.
+var jetStreamErrors = []struct {
+	source error
+	target error
+}{
+	{jetstream.ErrStreamNotFound, ErrStreamNotFound},
+	{jetstream.ErrStreamNameAlreadyInUse, ErrStreamExists},
+	{jetstream.ErrConsumerNotFound, ErrConsumerNotFound},
+	{jetstream.ErrConsumerDoesNotExist, ErrConsumerNotFound},
+	{jetstream.ErrConsumerExists, ErrConsumerExists},
+	{jetstream.ErrConsumerNameAlreadyInUse, ErrConsumerExists},
+	{jetstream.ErrMsgNotFound, ErrMsgNotFound},
+	{jetstream.ErrKeyNotFound, ErrKeyNotFound},
+	{jetstream.ErrJetStreamNotEnabled, ErrJetStreamNotEnabled},
+	{jetstream.ErrJetStreamNotEnabledForAccount, ErrJetStreamNotEnabled},
+	{nats.ErrTimeout, ErrTimeout},
+	{context.DeadlineExceeded, ErrTimeout},
+	{nats.ErrNoResponders, ErrNoResponders},
+	{jetstream.ErrNoStreamResponse, ErrNoResponders},
+	{&jetstream.APIError{ErrorCode: errCodeWrongLastSequence}, ErrWrongSequence},
+	{&jetstream.APIError{ErrorCode: errCodeWrongLastSequenceConstant}, ErrWrongSequence},
+	{nats.ErrConnectionClosed, ErrNotConnected},
+	{nats.ErrDisconnected, ErrNotConnected},
+}
+
+// JetStream API error codes without an exported nats.go error.
+const (
+	errCodeWrongLastSequence         jetstream.ErrorCode = 10071
+	errCodeWrongLastSequenceConstant jetstream.ErrorCode = 10164
+)
+
+// Error describes a failed rimnats operation. It wraps both the rimnats sentinel
+// (when one applies) and the underlying error so errors.Is matches either of them.
+type Error struct {
+	Op      string // Operation that failed, e.g. "subscribe"
+	Stream  string // Stream involved in the operation, if any
+	Subject string // Subject involved in the operation, if any
+	Kind    error  // Matching rimnats sentinel error, nil when none applies
+	Err     error  // Underlying error
+}
+
+// Error returns a message describing the operation context and the underlying error.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("rimnats: ")
+	b.WriteString(e.Op)
+	if e.Stream != "" {
+		fmt.Fprintf(&b, " stream=%s", e.Stream)
+	}
+	if e.Subject != "" {
+		fmt.Fprintf(&b, " subject=%s", e.Subject)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+
+	return b.String()
+}
+
+// Unwrap returns the sentinel and underlying errors for use with errors.Is and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// wrapError annotates err with the operation context and maps JetStream API errors
+// onto rimnats sentinels. Errors that are already wrapped are returned unchanged.
+func wrapError(op, stream, subject string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rerr *Error
+	if errors.As(err, &rerr) {
+		return err
+	}
+
+	return &Error{
+		Op:      op,
+		Stream:  stream,
+		Subject: subject,
+		Kind:    kindOf(err),
+		Err:     err,
+	}
+}
+
+// kindOf returns the rimnats sentinel matching err, or nil when there is none.
+func kindOf(err error) error {
+	for _, mapping := range jetStreamErrors {
+		if errors.Is(err, mapping.source) {
+			return mapping.target
+		}
+	}
+
+	return nil
+}
+
+// unmarshalError marks err, returned by a codec, as a decoding failure matching ErrUnmarshal.
+func unmarshalError(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnmarshal, err)
+}
@@ -0,0 +1,67 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStreamNotFoundError(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	_, err := client.Subscribe(context.Background(), "product.created", "missing", "errors_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error { return nil })
+	if err == nil {
+		t.Fatal("subscribe to a missing stream succeeded")
+	}
+
+	if !errors.Is(err, ErrStreamNotFound) {
+		t.Errorf("errors.Is(%v, ErrStreamNotFound) = false", err)
+	}
+	if !errors.Is(err, jetstream.ErrStreamNotFound) {
+		t.Errorf("errors.Is(%v, jetstream.ErrStreamNotFound) = false", err)
+	}
+
+	var rerr *Error
+	if !errors.As(err, &rerr) {
+		t.Fatalf("error %v is not a *Error", err)
+	}
+	if rerr.Op != "subscribe" || rerr.Stream != "missing" || rerr.Subject != "product.created" {
+		t.Errorf("error context = %q %q %q, want subscribe missing product.created", rerr.Op, rerr.Stream, rerr.Subject)
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{jetstream.ErrConsumerNotFound, ErrConsumerNotFound},
+		{fmt.Errorf("lookup: %w", jetstream.ErrMsgNotFound), ErrMsgNotFound},
+		{&jetstream.APIError{ErrorCode: errCodeWrongLastSequence, Code: 400}, ErrWrongSequence},
+		{context.DeadlineExceeded, ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		err := wrapError("op", "stream", "subject", tt.err)
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("wrapError(%v) does not match %v", tt.err, tt.kind)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("wrapError(%v) does not match the original error", tt.err)
+		}
+		if wrapError("other", "", "", err) != err {
+			t.Errorf("wrapError(%v) wrapped an already wrapped error again", tt.err)
+		}
+	}
+
+	if err := wrapError("op", "", "", errors.New("boom")).(*Error); err.Kind != nil {
+		t.Errorf("unmapped error got sentinel %v", err.Kind)
+	}
+}
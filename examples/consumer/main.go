@@ -17,7 +17,9 @@ var (
 )
 
 func init() {
-	client.Connect()
+	if err := client.Connect(); err != nil {
+		log.Fatalf("🚨 Failed to connect to NATS: %v", err)
+	}
 }
 
 func main() {
@@ -27,7 +29,7 @@ func main() {
 	ctx := context.Background()
 
 	// Subscribe to the "product.created" event
-	err := client.Subscribe(ctx, "product.created", "product_stream", "product_service",
+	_, err := client.Subscribe(ctx, "product.created", "product_stream", "product_service",
 		func() proto.Message {
 			return &v1.Event{} // Factory method to create a specific event type
 		}, func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
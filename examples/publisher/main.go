@@ -18,7 +18,9 @@ var (
 )
 
 func init() {
-	client.Connect()
+	if err := client.Connect(); err != nil {
+		log.Fatalf("🚨 Failed to connect to NATS: %v", err)
+	}
 }
 
 func main() {
@@ -27,7 +29,7 @@ func main() {
 	ctx := context.Background()
 
 	// Initialize the event bus
-	if err := client.CreateStream(ctx, jetstream.StreamConfig{
+	if _, err := client.CreateStream(ctx, jetstream.StreamConfig{
 		Name:        "product_stream",
 		Description: "Sample events",
 		Subjects:    []string{"product.>"},
@@ -61,7 +63,7 @@ func main() {
 		}
 
 		// Publish the event
-		if err := client.Publish(ctx, subject, event); err != nil {
+		if _, err := client.Publish(ctx, subject, event); err != nil {
 			log.Fatalf("🚨 Failed to publish event: %v", err)
 		}
 
@@ -3,10 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/rimdesk/rimnats-go"
 	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
-	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -14,19 +14,22 @@ var (
 )
 
 func init() {
-	client.Connect()
+	if err := client.Connect(); err != nil {
+		log.Fatalf("🚨 Failed to connect to NATS: %v", err)
+	}
 }
 
 func main() {
 	defer client.Close()
 
-	_ = client.Reply("example.say.hello",
-		func() proto.Message { return &v1.SayHelloRequest{} },
-		func(ctx context.Context, req proto.Message) (proto.Message, error) {
-			request := req.(*v1.SayHelloRequest)
+	err := rimnats.ReplyTyped(client, "example.say.hello",
+		func(ctx context.Context, request *v1.SayHelloRequest) (*v1.SayHelloResponse, error) {
 			return &v1.SayHelloResponse{Message: fmt.Sprintf("Hello Reply %s", request.GetName())}, nil
 		},
 	)
+	if err != nil {
+		log.Fatalf("🚨 Failed to register reply handler: %v", err)
+	}
 
 	select {}
 }
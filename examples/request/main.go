@@ -7,7 +7,6 @@ import (
 
 	"github.com/rimdesk/rimnats-go"
 	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
-	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -15,19 +14,20 @@ var (
 )
 
 func init() {
-	client.Connect()
+	if err := client.Connect(); err != nil {
+		log.Fatalf("🚨 Failed to connect to NATS: %v", err)
+	}
 }
 
 func main() {
 	defer client.Close()
 
 	ctx := context.Background()
-	resp, err := client.Request(ctx, "example.say.hello", &v1.SayHelloRequest{Name: "Joey"}, func() proto.Message { return &v1.SayHelloResponse{} }, 3*time.Second)
+	response, err := rimnats.RequestTyped[*v1.SayHelloRequest, *v1.SayHelloResponse](ctx, client,
+		"example.say.hello", &v1.SayHelloRequest{Name: "Joey"}, 3*time.Second)
 	if err != nil {
-		log.Println("🚨 response error:", err)
+		log.Fatalln("🚨 response error:", err)
 	}
 
-	response := resp.(*v1.SayHelloResponse)
-
 	log.Println("==== 📣 Response==== :", response.GetMessage())
 }
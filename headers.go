@@ -0,0 +1,36 @@
+package rimnats
+
+// NATS headers used by rimnats to exchange metadata alongside message payloads.
+const (
+	// HeaderContentType carries the MIME type of the payload, e.g. "application/json", so
+	// consumers can pick the matching codec.
+	HeaderContentType = "Content-Type"
+
+	// HeaderMessageType carries the fully-qualified protobuf name of the published message,
+	// e.g. "rimdesk.rimnats.v1.Event". Subscribers reject messages of another type.
+	HeaderMessageType = "Rimnats-Message-Type"
+
+	// HeaderSchemaVersion carries the schema version set with WithSchemaVersion.
+	HeaderSchemaVersion = "Rimnats-Schema-Version"
+
+	// HeaderHeartbeat marks heartbeat messages sent by a streaming responder. On a request
+	// it signals that the requester accepts heartbeats before the final response.
+	HeaderHeartbeat = "Rimnats-Heartbeat"
+
+	// HeaderTimeout carries the time the requester is still willing to wait for a response,
+	// formatted as a Go duration such as "4.5s". Reply handlers get a context with this deadline.
+	HeaderTimeout = "Rimnats-Timeout"
+
+	// HeaderRequestID identifies a request across retries so responders can deduplicate it.
+	HeaderRequestID = "Rimnats-Request-Id"
+
+	// HeaderDeliveryCount carries the number of times a dead-lettered message was delivered.
+	HeaderDeliveryCount = "Nats-Delivery-Count"
+
+	// HeaderServiceError carries the error description on a response whose handler failed.
+	// The name matches the NATS micro framework so its clients understand rimnats replies.
+	HeaderServiceError = "Nats-Service-Error"
+
+	// HeaderServiceErrorCode carries the error code on a response whose handler failed.
+	HeaderServiceErrorCode = "Nats-Service-Error-Code"
+)
@@ -0,0 +1,27 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// HealthCheck reports whether the client can reach NATS and JetStream, for use in readiness
+// probes. It returns an error matching ErrDisconnected when the connection is not CONNECTED and
+// one matching ErrJetStreamUnavailable when the account info request fails within ctx.
+func (n *rimNats) HealthCheck(ctx context.Context) error {
+	if n.conn == nil {
+		return wrapError("health check", "", "", ErrDisconnected)
+	}
+
+	if status := n.conn.Status(); status != nats.CONNECTED {
+		return wrapError("health check", "", "", fmt.Errorf("%w: connection is %s", ErrDisconnected, status))
+	}
+
+	if _, err := n.js.AccountInfo(ctx); err != nil {
+		return wrapError("health check", "", "", fmt.Errorf("%w: %w", ErrJetStreamUnavailable, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// testLogger records log messages so tests can assert on them without writing to the console.
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Debug(msg string, args ...any) { l.record("DEBUG", msg, args) }
+func (l *testLogger) Info(msg string, args ...any)  { l.record("INFO", msg, args) }
+func (l *testLogger) Warn(msg string, args ...any)  { l.record("WARN", msg, args) }
+func (l *testLogger) Error(msg string, args ...any) { l.record("ERROR", msg, args) }
+
+func (l *testLogger) record(level, msg string, args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lines = append(l.lines, level+" "+formatLine(msg, args))
+}
+
+// contains reports whether a line was logged at level containing text.
+func (l *testLogger) contains(level, text string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, line := range l.lines {
+		if strings.HasPrefix(line, level+" ") && strings.Contains(line, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newTestClient connects a client to the server at url and closes it when the test ends.
+// Logs go to a testLogger unless opts set another logger.
+func newTestClient(t *testing.T, url string, opts ...Option) *rimNats {
+	t.Helper()
+
+	client := New(url, append([]Option{WithLogger(&testLogger{})}, opts...)...).(*rimNats)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// createTestStream creates a stream named name capturing subjects.
+func createTestStream(t *testing.T, client Client, name string, subjects ...string) jetstream.Stream {
+	t.Helper()
+
+	stream, err := client.CreateStream(context.Background(), jetstream.StreamConfig{Name: name, Subjects: subjects})
+	if err != nil {
+		t.Fatalf("create stream %s: %v", name, err)
+	}
+
+	return stream
+}
+
+// eventually fails the test unless cond becomes true within timeout.
+func eventually(t *testing.T, timeout time.Duration, cond func() bool, format string, args ...any) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out: %s", fmt.Sprintf(format, args...))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
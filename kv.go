@@ -0,0 +1,146 @@
+package rimnats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// KVEntry is a protobuf value stored in a KVStore.
+type KVEntry struct {
+	Key       string               // Key the value is stored under
+	Value     proto.Message        // Decoded value, nil for deletes and purges
+	Revision  uint64               // Revision of the key in the bucket
+	Operation jetstream.KeyValueOp // Put, delete or purge
+}
+
+// KVStore stores protobuf values in a JetStream key-value bucket. Values are encoded with
+// the client's event codec.
+type KVStore struct {
+	client *rimNats
+	bucket jetstream.KeyValue
+}
+
+// KV returns the key-value bucket named bucket, which must already exist.
+func (n *rimNats) KV(ctx context.Context, bucket string) (*KVStore, error) {
+	kv, err := n.js.KeyValue(ctx, bucket)
+	if err != nil {
+		return nil, wrapError("kv", bucket, "", err)
+	}
+
+	return &KVStore{client: n, bucket: kv}, nil
+}
+
+// CreateKVBucket creates the key-value bucket described by config, or updates it if it already exists.
+func (n *rimNats) CreateKVBucket(ctx context.Context, config jetstream.KeyValueConfig) (*KVStore, error) {
+	kv, err := n.js.CreateOrUpdateKeyValue(ctx, config)
+	if err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to create kv bucket", "bucket", config.Bucket, "error", err)
+		return nil, wrapError("create kv bucket", config.Bucket, "", err)
+	}
+
+	return &KVStore{client: n, bucket: kv}, nil
+}
+
+// Bucket returns the underlying JetStream key-value bucket.
+func (s *KVStore) Bucket() jetstream.KeyValue {
+	return s.bucket
+}
+
+// Get returns the latest value of key decoded into a message created by factory.
+// It returns an error matching ErrKeyNotFound when the key does not exist or was deleted.
+func (s *KVStore) Get(ctx context.Context, key string, factory func() proto.Message) (*KVEntry, error) {
+	entry, err := s.bucket.Get(ctx, key)
+	if err != nil {
+		return nil, wrapError("kv get", s.bucket.Bucket(), key, err)
+	}
+
+	return s.decode(entry, factory)
+}
+
+// Put stores msg under key and returns the new revision of the key.
+func (s *KVStore) Put(ctx context.Context, key string, msg proto.Message) (uint64, error) {
+	data, err := s.client.cfg.EventCodec.Marshal(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	revision, err := s.bucket.Put(ctx, key, data)
+	if err != nil {
+		return 0, wrapError("kv put", s.bucket.Bucket(), key, err)
+	}
+
+	return revision, nil
+}
+
+// Delete marks key as deleted, keeping its history.
+func (s *KVStore) Delete(ctx context.Context, key string) error {
+	if err := s.bucket.Delete(ctx, key); err != nil {
+		return wrapError("kv delete", s.bucket.Bucket(), key, err)
+	}
+
+	return nil
+}
+
+// Watch streams changes to the keys matching keys, which may contain wildcards, as decoded
+// entries. The current values are delivered first, followed by every later change. Deletes
+// and purges are delivered with a nil Value. The channel is closed once ctx is done.
+func (s *KVStore) Watch(ctx context.Context, keys string, factory func() proto.Message) (<-chan *KVEntry, error) {
+	watcher, err := s.bucket.Watch(ctx, keys)
+	if err != nil {
+		return nil, wrapError("kv watch", s.bucket.Bucket(), keys, err)
+	}
+
+	entries := make(chan *KVEntry)
+	go func() {
+		defer close(entries)
+		defer func() { _ = watcher.Stop() }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-watcher.Updates():
+				if !ok {
+					return
+				}
+
+				// A nil update marks the end of the initial values
+				if update == nil {
+					continue
+				}
+
+				entry, err := s.decode(update, factory)
+				if err != nil {
+					s.client.loggR.Error("❌ [ rimnats ]: failed to decode kv entry", "bucket", s.bucket.Bucket(), "key", update.Key(), "error", err)
+					continue
+				}
+
+				select {
+				case entries <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return entries, nil
+}
+
+// decode turns a JetStream key-value entry into a KVEntry.
+func (s *KVStore) decode(entry jetstream.KeyValueEntry, factory func() proto.Message) (*KVEntry, error) {
+	decoded := &KVEntry{Key: entry.Key(), Revision: entry.Revision(), Operation: entry.Operation()}
+	if entry.Operation() != jetstream.KeyValuePut {
+		return decoded, nil
+	}
+
+	value := factory()
+	if err := s.client.cfg.EventCodec.Unmarshal(entry.Value(), value); err != nil {
+		return nil, unmarshalError(err)
+	}
+
+	decoded.Value = value
+	return decoded, nil
+}
@@ -0,0 +1,35 @@
+package rimnats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ConsumerInfo returns the current state of the consumer durable on stream, including its
+// delivered and acknowledged sequences and pending counts.
+func (n *rimNats) ConsumerInfo(ctx context.Context, stream, durable string) (*jetstream.ConsumerInfo, error) {
+	consumer, err := n.js.Consumer(ctx, stream, durable)
+	if err != nil {
+		return nil, wrapError("consumer info", stream, "", err)
+	}
+
+	info, err := consumer.Info(ctx)
+	if err != nil {
+		return nil, wrapError("consumer info", stream, "", err)
+	}
+
+	return info, nil
+}
+
+// PendingMessages reports how far the consumer durable on stream is behind: the number of
+// messages not yet delivered plus those delivered but not yet acknowledged. It suits
+// lag-based autoscaling and alerting.
+func (n *rimNats) PendingMessages(ctx context.Context, stream, durable string) (uint64, error) {
+	info, err := n.ConsumerInfo(ctx, stream, durable)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.NumPending + uint64(info.NumAckPending), nil
+}
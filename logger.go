@@ -0,0 +1,95 @@
+package rimnats
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/beego/beego/v2/core/logs"
+)
+
+// Logger is the logging interface used by rimnats. Messages are accompanied by
+// alternating key/value pairs, e.g. Info("published message", "subject", subject).
+// Implement it to route rimnats logs into an existing logging pipeline.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// beegoLogger adapts a Beego logger to the Logger interface. It is the default logger.
+type beegoLogger struct {
+	logger *logs.BeeLogger
+}
+
+// NewBeegoLogger wraps a Beego logger so it can be passed to WithLogger.
+func NewBeegoLogger(logger *logs.BeeLogger) Logger {
+	return &beegoLogger{logger: logger}
+}
+
+func (l *beegoLogger) Debug(msg string, args ...any) {
+	l.logger.Debug("%s", formatLine(msg, args))
+}
+
+func (l *beegoLogger) Info(msg string, args ...any) {
+	l.logger.Info("%s", formatLine(msg, args))
+}
+
+func (l *beegoLogger) Warn(msg string, args ...any) {
+	l.logger.Warn("%s", formatLine(msg, args))
+}
+
+func (l *beegoLogger) Error(msg string, args ...any) {
+	l.logger.Error("%s", formatLine(msg, args))
+}
+
+// formatLine renders the message followed by its key/value pairs as "key=value".
+func formatLine(msg string, args []any) string {
+	if len(args) == 0 {
+		return msg
+	}
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", args[i])
+		}
+	}
+
+	return b.String()
+}
+
+// slogLogger adapts a log/slog logger to the Logger interface.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger wraps a slog logger so it can be passed to WithLogger. Key/value pairs such as
+// subject, stream and durable are emitted as slog attributes rather than interpolated into the message.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &slogLogger{logger: logger.With("component", "rimnats")}
+}
+
+func (l *slogLogger) Debug(msg string, args ...any) {
+	l.logger.Debug(msg, args...)
+}
+
+func (l *slogLogger) Info(msg string, args ...any) {
+	l.logger.Info(msg, args...)
+}
+
+func (l *slogLogger) Warn(msg string, args ...any) {
+	l.logger.Warn(msg, args...)
+}
+
+func (l *slogLogger) Error(msg string, args ...any) {
+	l.logger.Error(msg, args...)
+}
@@ -0,0 +1,678 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nats.go/micro"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// memoryClient is a Client that delivers messages in memory without a NATS server.
+type memoryClient struct {
+	engine     *rimNats           // Client whose encoding, decoding and handler plumbing is reused
+	mu         sync.RWMutex       // Guards consumers, responders and sequence
+	consumers  []*memoryConsumer  // Subscriptions receiving published messages
+	responders []*memoryResponder // Handlers answering requests
+	sequence   uint64             // Sequence of the last published message
+}
+
+// memoryConsumer is a subscription registered on a memoryClient.
+type memoryConsumer struct {
+	subjects []string
+	stream   string
+	durable  string
+	factory  func() proto.Message
+	handler  ProtoHandler
+	options  *subscribeOptions
+}
+
+// memoryResponder is a request handler registered on a memoryClient.
+type memoryResponder struct {
+	subject    string
+	reqFactory func() proto.Message
+	handler    StreamingHandler
+}
+
+// Ensure the in-memory client satisfies the Client interface at compile time.
+var _ Client = (*memoryClient)(nil)
+
+// NewInMemory creates a Client that works entirely in memory, so code depending on Client can
+// be unit tested without a NATS server. Publish synchronously calls the handlers of every
+// subscription whose subject matches, and Request calls the first matching Reply handler.
+// Messages and requests are still encoded and decoded with the configured codecs.
+//
+// Messages are not stored: they are delivered once to the subscriptions existing at publish
+// time and NAKs do not cause redelivery. Reading stored messages, key-value buckets,
+// consumer info and micro services return ErrNotSupported; stream management succeeds without effect.
+func NewInMemory(opts ...Option) Client {
+	cfg := getConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = getLogger()
+	}
+
+	factories := cfg.Factories
+	if factories == nil {
+		factories = NewFactoryRegistry()
+	}
+
+	engine := &rimNats{cfg: cfg, loggR: logger, hooks: &connectionHooks{}, factories: factories}
+	engine.registerMetrics()
+	engine.subs = newSubscriptionSet(cfg.Metrics)
+
+	return &memoryClient{engine: engine}
+}
+
+// AddService is not supported by the in-memory client.
+func (c *memoryClient) AddService(config micro.Config) (*Service, error) {
+	return nil, wrapError("add service", "", "", ErrNotSupported)
+}
+
+// Close removes every subscription and request handler.
+func (c *memoryClient) Close() {
+	c.mu.Lock()
+	c.consumers = nil
+	c.responders = nil
+	c.mu.Unlock()
+
+	c.engine.subs.clear()
+}
+
+// Connect does nothing; the in-memory client is always connected.
+func (c *memoryClient) Connect() error {
+	return nil
+}
+
+// Drain closes the client. Handlers run synchronously, so no message is in flight.
+func (c *memoryClient) Drain(ctx context.Context) error {
+	c.Close()
+	return nil
+}
+
+// DrainStreamConsumers stops the subscriptions bound to stream.
+func (c *memoryClient) DrainStreamConsumers(ctx context.Context, stream string) error {
+	for _, s := range c.engine.subs.list() {
+		if s.stream == stream {
+			s.Stop()
+		}
+	}
+
+	return nil
+}
+
+// Shutdown closes the client once the handlers already running have finished.
+func (c *memoryClient) Shutdown(ctx context.Context) error {
+	err := c.engine.Shutdown(ctx)
+	c.Close()
+	return err
+}
+
+// Status always reports a connected client.
+func (c *memoryClient) Status() nats.Status {
+	return nats.CONNECTED
+}
+
+// HealthCheck always succeeds.
+func (c *memoryClient) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// ServerInfo reports no server version with JetStream enabled.
+func (c *memoryClient) ServerInfo() (version string, jetStreamEnabled bool, err error) {
+	return "", true, nil
+}
+
+// SupportsPerMessageTTL always reports false.
+func (c *memoryClient) SupportsPerMessageTTL() bool {
+	return false
+}
+
+// SupportsSubjectTransforms always reports false.
+func (c *memoryClient) SupportsSubjectTransforms() bool {
+	return false
+}
+
+// OnDisconnect does nothing; the in-memory client never disconnects.
+func (c *memoryClient) OnDisconnect(fn func(*nats.Conn, error)) {}
+
+// OnReconnect does nothing; the in-memory client never reconnects.
+func (c *memoryClient) OnReconnect(fn func(*nats.Conn)) {}
+
+// OnClosed does nothing; the in-memory client has no connection to close.
+func (c *memoryClient) OnClosed(fn func(*nats.Conn)) {}
+
+// Fetch is not supported by the in-memory client.
+func (c *memoryClient) Fetch(ctx context.Context, subject, stream, durable string, batch int, factory func() proto.Message, opts ...SubscribeOption) ([]DecodedMsg, error) {
+	return nil, wrapError("fetch", stream, subject, ErrNotSupported)
+}
+
+// GetEngine returns the client whose plumbing the in-memory client reuses. It has no connection.
+func (c *memoryClient) GetEngine() *rimNats {
+	return c.engine
+}
+
+// GetLastMsg is not supported by the in-memory client.
+func (c *memoryClient) GetLastMsg(ctx context.Context, stream, subject string) (proto.Message, *jetstream.RawStreamMsg, error) {
+	return nil, nil, wrapError("get last msg", stream, subject, ErrNotSupported)
+}
+
+// GetMsg is not supported by the in-memory client.
+func (c *memoryClient) GetMsg(ctx context.Context, stream string, seq uint64, factory func() proto.Message) (proto.Message, *jetstream.RawStreamMsg, error) {
+	return nil, nil, wrapError("get msg", stream, "", ErrNotSupported)
+}
+
+// JetStream returns nil; the in-memory client has no JetStream context.
+func (c *memoryClient) JetStream() jetstream.JetStream {
+	return nil
+}
+
+// AccountInfo is not supported by the in-memory client.
+func (c *memoryClient) AccountInfo(ctx context.Context) (*jetstream.AccountInfo, error) {
+	return nil, wrapError("account info", "", "", ErrNotSupported)
+}
+
+// ConsumerInfo is not supported by the in-memory client.
+func (c *memoryClient) ConsumerInfo(ctx context.Context, stream, durable string) (*jetstream.ConsumerInfo, error) {
+	return nil, wrapError("consumer info", stream, "", ErrNotSupported)
+}
+
+// CreateKVBucket is not supported by the in-memory client.
+func (c *memoryClient) CreateKVBucket(ctx context.Context, config jetstream.KeyValueConfig) (*KVStore, error) {
+	return nil, wrapError("create kv bucket", config.Bucket, "", ErrNotSupported)
+}
+
+// KV is not supported by the in-memory client.
+func (c *memoryClient) KV(ctx context.Context, bucket string) (*KVStore, error) {
+	return nil, wrapError("kv", bucket, "", ErrNotSupported)
+}
+
+// CreateStream succeeds without effect and returns a nil stream, so setup code runs unchanged.
+func (c *memoryClient) CreateStream(ctx context.Context, config jetstream.StreamConfig) (jetstream.Stream, error) {
+	return nil, nil
+}
+
+// CreateMirror succeeds without effect and returns a nil stream.
+func (c *memoryClient) CreateMirror(ctx context.Context, name, sourceStream string, opts ...StreamOption) (jetstream.Stream, error) {
+	return nil, nil
+}
+
+// CreateSourcedStream succeeds without effect and returns a nil stream.
+func (c *memoryClient) CreateSourcedStream(ctx context.Context, name string, sources []string, opts ...StreamOption) (jetstream.Stream, error) {
+	return nil, nil
+}
+
+// CreateWorkQueue succeeds without effect and returns a nil stream.
+func (c *memoryClient) CreateWorkQueue(ctx context.Context, name string, subjects []string, opts ...StreamOption) (jetstream.Stream, error) {
+	return nil, nil
+}
+
+// AddStreamSubject succeeds without effect; the in-memory client has no streams.
+func (c *memoryClient) AddStreamSubject(ctx context.Context, name, subject string) error {
+	return nil
+}
+
+// RemoveStreamSubject succeeds without effect; the in-memory client has no streams.
+func (c *memoryClient) RemoveStreamSubject(ctx context.Context, name, subject string) error {
+	return nil
+}
+
+// DeleteStream succeeds without effect.
+func (c *memoryClient) DeleteStream(ctx context.Context, name string) error {
+	return nil
+}
+
+// PurgeStream succeeds without effect; the in-memory client stores no messages.
+func (c *memoryClient) PurgeStream(ctx context.Context, name string, opts ...jetstream.StreamPurgeOpt) error {
+	return nil
+}
+
+// PauseConsumer is not supported by the in-memory client.
+func (c *memoryClient) PauseConsumer(ctx context.Context, stream, durable string, until time.Time) (*jetstream.ConsumerPauseResponse, error) {
+	return nil, wrapError("pause consumer", stream, "", ErrNotSupported)
+}
+
+// ResumeConsumer is not supported by the in-memory client.
+func (c *memoryClient) ResumeConsumer(ctx context.Context, stream, durable string) (*jetstream.ConsumerPauseResponse, error) {
+	return nil, wrapError("resume consumer", stream, "", ErrNotSupported)
+}
+
+// PendingMessages always reports zero; messages are handled as soon as they are published.
+func (c *memoryClient) PendingMessages(ctx context.Context, stream, durable string) (uint64, error) {
+	return 0, nil
+}
+
+// Publish delivers msg to every subscription whose subject matches.
+func (c *memoryClient) Publish(ctx context.Context, subject string, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	return c.PublishMsg(ctx, &Message{Subject: subject, Proto: msg}, opts...)
+}
+
+// PublishToStream delivers msg like Publish; the in-memory client has no streams to check.
+func (c *memoryClient) PublishToStream(ctx context.Context, stream, subject string, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	return c.Publish(ctx, subject, msg, opts...)
+}
+
+// PublishAsync delivers msg like Publish and returns an already resolved future.
+func (c *memoryClient) PublishAsync(ctx context.Context, subject string, msg proto.Message, opts ...PublishOption) (jetstream.PubAckFuture, error) {
+	natsMsg, err := c.engine.encodeMsg(&Message{Subject: subject, Proto: msg}, newPublishOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	future := &memoryFuture{msg: natsMsg, ok: make(chan *jetstream.PubAck, 1), err: make(chan error, 1)}
+	future.ok <- c.deliver(ctx, natsMsg)
+
+	return future, nil
+}
+
+// Flush returns immediately; publishes are delivered synchronously.
+func (c *memoryClient) Flush(ctx context.Context) error {
+	return nil
+}
+
+// PublishAsyncComplete returns a closed channel; asynchronous publishes resolve immediately.
+func (c *memoryClient) PublishAsyncComplete() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+// PublishMsg delivers msg with its headers to every subscription whose subject matches.
+// The acknowledgement reports the stream of the first matching subscription.
+func (c *memoryClient) PublishMsg(ctx context.Context, msg *Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	natsMsg, err := c.engine.encodeMsg(msg, newPublishOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.deliver(ctx, natsMsg), nil
+}
+
+// deliver hands an encoded message to every subscription whose subject matches and
+// returns the acknowledgement for it.
+func (c *memoryClient) deliver(ctx context.Context, natsMsg *nats.Msg) *jetstream.PubAck {
+	c.engine.propagator().Inject(ctx, headerCarrier(natsMsg.Header))
+
+	c.mu.Lock()
+	c.sequence++
+	sequence := c.sequence
+	var consumers []*memoryConsumer
+	for _, consumer := range c.consumers {
+		if consumer.matches(natsMsg.Subject) {
+			consumers = append(consumers, consumer)
+		}
+	}
+	c.mu.Unlock()
+
+	ack := &jetstream.PubAck{Sequence: sequence}
+	for _, consumer := range consumers {
+		if ack.Stream == "" {
+			ack.Stream = consumer.stream
+		}
+
+		m := &memoryMsg{msg: natsMsg, stream: consumer.stream, consumer: consumer.durable, sequence: sequence}
+		c.engine.handle(ctx, m, consumer.factory, consumer.handler, consumer.options)
+	}
+
+	return ack
+}
+
+// PublishBatch publishes msgs to subject one after the other.
+func (c *memoryClient) PublishBatch(ctx context.Context, subject string, msgs []proto.Message, opts ...PublishOption) error {
+	var errs []error
+	for i, msg := range msgs {
+		if _, err := c.Publish(ctx, subject, msg, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("message %d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Pull is not supported by the in-memory client.
+func (c *memoryClient) Pull(ctx context.Context, subject, stream, durable string, batch int, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (int, error) {
+	return 0, wrapError("pull", stream, subject, ErrNotSupported)
+}
+
+// Reply registers handler for requests on subject.
+func (c *memoryClient) Reply(subject string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...ReplyOption) error {
+	return c.ReplyStreaming(subject, reqFactory, func(ctx context.Context, req proto.Message, _ func() error) (proto.Message, error) {
+		return handler(ctx, req)
+	}, opts...)
+}
+
+// ReplyQueue registers handler for requests on subject. Requests are always answered by the
+// first matching handler, so queue groups make no difference in memory.
+func (c *memoryClient) ReplyQueue(subject, queue string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...ReplyOption) error {
+	return c.Reply(subject, reqFactory, handler, opts...)
+}
+
+// ReplyStreaming registers handler for requests on subject. Heartbeats invoke the
+// requester's WithHeartbeat callback.
+func (c *memoryClient) ReplyStreaming(subject string, reqFactory func() proto.Message, handler StreamingHandler, opts ...ReplyOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responders = append(c.responders, &memoryResponder{subject: subject, reqFactory: reqFactory, handler: handler})
+	return nil
+}
+
+// RegisterFactory registers factory for messages on subject in the client's factory registry.
+func (c *memoryClient) RegisterFactory(subject string, factory func() proto.Message) {
+	c.engine.RegisterFactory(subject, factory)
+}
+
+// Request calls the first Reply handler matching subject and returns its response.
+func (c *memoryClient) Request(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, opts ...RequestOption) (proto.Message, error) {
+	resp, err := c.RequestFull(ctx, subject, req, factory, timeout, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	return resp.Message, nil
+}
+
+// RequestMany calls every Reply handler matching subject, up to maxResponses of them, and
+// returns their successful responses.
+func (c *memoryClient) RequestMany(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, maxResponses int, window time.Duration) ([]proto.Message, error) {
+	responders := c.matchResponders(subject)
+	if len(responders) == 0 {
+		return nil, wrapError("request many", "", subject, nats.ErrNoResponders)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	var replies []proto.Message
+	for _, responder := range responders {
+		if maxResponses > 0 && len(replies) == maxResponses {
+			break
+		}
+
+		resp, err := c.call(ctx, responder, subject, req, factory, &requestOptions{})
+		if err != nil || resp.Error != nil {
+			continue
+		}
+
+		replies = append(replies, resp.Message)
+	}
+
+	return replies, nil
+}
+
+// RequestFull calls the first Reply handler matching subject and returns its response with
+// the error reported by the handler, if any.
+func (c *memoryClient) RequestFull(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, opts ...RequestOption) (*Response, error) {
+	var options requestOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	responders := c.matchResponders(subject)
+	if len(responders) == 0 {
+		return nil, wrapError("request", "", subject, nats.ErrNoResponders)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return c.call(ctx, responders[0], subject, req, factory, &options)
+}
+
+// call passes req through the RPC codec to responder and decodes its response into a message
+// created by factory.
+func (c *memoryClient) call(ctx context.Context, responder *memoryResponder, subject string, req proto.Message, factory func() proto.Message, options *requestOptions) (*Response, error) {
+	codec := c.engine.cfg.RPCCodec
+
+	data, err := codec.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := responder.reqFactory()
+	if err := codec.Unmarshal(data, decoded); err != nil {
+		return &Response{Subject: subject, Error: &ServiceError{Code: "400", Description: err.Error()}}, nil
+	}
+
+	heartbeat := func() error {
+		if options.heartbeat != nil {
+			options.heartbeat()
+		}
+		return nil
+	}
+
+	ctx, span := c.engine.startSpan(ctx, "reply", subject, trace.SpanKindServer)
+	defer span.End()
+
+	resp, err := c.engine.callReplyHandler(ctx, subject, responder.handler, decoded, heartbeat)
+	if err == nil {
+		data, err = codec.Marshal(resp)
+	}
+
+	if err != nil {
+		recordSpanError(span, err)
+
+		serviceErr := &ServiceError{Code: "500", Description: err.Error()}
+		errors.As(err, &serviceErr)
+		return &Response{Subject: subject, Error: serviceErr}, nil
+	}
+
+	reply := factory()
+	if err := codec.Unmarshal(data, reply); err != nil {
+		return nil, unmarshalError(err)
+	}
+
+	return &Response{Message: reply, Header: nats.Header{HeaderContentType: {codec.ContentType()}}, Subject: subject}, nil
+}
+
+// matchResponders returns the request handlers whose subject matches subject.
+func (c *memoryClient) matchResponders(subject string) []*memoryResponder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var responders []*memoryResponder
+	for _, responder := range c.responders {
+		if subjectMatches(responder.subject, subject) {
+			responders = append(responders, responder)
+		}
+	}
+
+	return responders
+}
+
+// SubscribePartition registers handler for messages published on one partition of subject.
+func (c *memoryClient) SubscribePartition(ctx context.Context, subject string, partition int, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	if partition < 0 {
+		return nil, wrapError("subscribe", stream, subject, errors.New("negative partition"))
+	}
+
+	return c.Subscribe(ctx, partitionSubject(subject, partition), stream, partitionDurable(durable, partition), factory, handler, opts...)
+}
+
+// Subscribe registers handler for messages published on subject.
+func (c *memoryClient) Subscribe(ctx context.Context, subject, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	return c.subscribe(ctx, []string{subject}, stream, durable, factory, handler, opts...)
+}
+
+// SubscribeMulti registers handler for messages published on any of subjects.
+func (c *memoryClient) SubscribeMulti(ctx context.Context, subjects []string, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	if len(subjects) == 0 {
+		return nil, wrapError("subscribe", stream, "", errors.New("no subjects"))
+	}
+
+	return c.subscribe(ctx, subjects, stream, durable, factory, handler, opts...)
+}
+
+// SubscribeAny registers routes for the Any payloads of envelopes published on subject.
+func (c *memoryClient) SubscribeAny(ctx context.Context, subject, stream, durable string, factory func() proto.Message, routes map[string]AnyRoute, opts ...SubscribeOption) (*Subscription, error) {
+	return c.Subscribe(ctx, subject, stream, durable, factory, dispatchAny(routes), opts...)
+}
+
+// SubscribeChan queues messages published on subject on the returned channel.
+func (c *memoryClient) SubscribeChan(ctx context.Context, subject, stream, durable string, factory func() proto.Message, opts ...SubscribeOption) (<-chan DecodedMsg, error) {
+	return subscribeChan(ctx, func(handler ProtoHandler) (*Subscription, error) {
+		return c.Subscribe(ctx, subject, stream, durable, factory, handler, opts...)
+	})
+}
+
+// SubscribeOneof registers handlers for the oneof payloads of envelopes published on subject.
+func (c *memoryClient) SubscribeOneof(ctx context.Context, subject, stream, durable string, factory func() proto.Message, handlers map[protoreflect.Name]ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	return c.Subscribe(ctx, subject, stream, durable, factory, dispatchOneof(handlers), opts...)
+}
+
+// SubscribeRegistered works like Subscribe but decodes messages with the factory registered for subject.
+func (c *memoryClient) SubscribeRegistered(ctx context.Context, subject, stream, durable string, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	factory, ok := c.engine.factories.Factory(subject)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoFactory, subject)
+	}
+
+	return c.Subscribe(ctx, subject, stream, durable, factory, handler, opts...)
+}
+
+// SubscribeRenamed registers handler for messages published on either oldSubject or newSubject.
+func (c *memoryClient) SubscribeRenamed(ctx context.Context, oldSubject, newSubject, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	return c.subscribe(ctx, []string{oldSubject, newSubject}, stream, durable, factory, handler, opts...)
+}
+
+// SubscribeRouter dispatches messages published on subject to the routes of router.
+func (c *memoryClient) SubscribeRouter(ctx context.Context, subject, stream, durable string, router *Router, opts ...SubscribeOption) (*Subscription, error) {
+	opts = append(opts, func(o *subscribeOptions) {
+		o.factoryFor = router.factory
+	})
+
+	return c.subscribe(ctx, []string{subject}, stream, durable, nil, router.dispatch, opts...)
+}
+
+// subscribe registers a consumer on subjects until ctx is cancelled or the subscription is stopped.
+func (c *memoryClient) subscribe(ctx context.Context, subjects []string, stream, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) (*Subscription, error) {
+	options := newSubscribeOptions(opts)
+	// Dead letters are republished on the connection, which the in-memory client does not have
+	options.deadLetterSubject = nil
+
+	consumer := &memoryConsumer{subjects: subjects, stream: stream, durable: durable, factory: factory, handler: handler, options: options}
+
+	c.mu.Lock()
+	c.consumers = append(c.consumers, consumer)
+	c.mu.Unlock()
+
+	stopped := make(chan struct{})
+	var once sync.Once
+	sub := &Subscription{subject: subjects[0], stream: stream, consumer: durable, result: ConsumerCreated, stop: func() {
+		once.Do(func() {
+			c.removeConsumer(consumer)
+			close(stopped)
+		})
+	}}
+	c.engine.subs.add(sub)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Stop()
+		case <-stopped:
+		}
+	}()
+
+	return sub, nil
+}
+
+// removeConsumer stops delivering messages to consumer.
+func (c *memoryClient) removeConsumer(consumer *memoryConsumer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, existing := range c.consumers {
+		if existing == consumer {
+			c.consumers = append(c.consumers[:i], c.consumers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Replay is not supported by the in-memory client.
+func (c *memoryClient) Replay(ctx context.Context, stream, subject string, startSeq uint64, handler ProtoHandler, opts ...SubscribeOption) error {
+	return wrapError("replay", stream, subject, ErrNotSupported)
+}
+
+// TailLast is not supported by the in-memory client.
+func (c *memoryClient) TailLast(ctx context.Context, stream, subject string, count int, factory func() proto.Message) ([]proto.Message, error) {
+	return nil, wrapError("tail last", stream, subject, ErrNotSupported)
+}
+
+// Use registers middlewares around every subscription handler.
+func (c *memoryClient) Use(middlewares ...Middleware) {
+	c.engine.Use(middlewares...)
+}
+
+// matches reports whether the consumer receives messages published on subject.
+func (c *memoryConsumer) matches(subject string) bool {
+	for _, pattern := range c.subjects {
+		if subjectMatches(pattern, subject) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// memoryMsg is a message delivered by the in-memory client. Acknowledgements are accepted
+// and have no effect.
+type memoryMsg struct {
+	msg      *nats.Msg
+	stream   string
+	consumer string
+	sequence uint64
+}
+
+// Metadata reports the message as delivered once at its publish sequence.
+func (m *memoryMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{
+		Sequence:     jetstream.SequencePair{Stream: m.sequence, Consumer: m.sequence},
+		NumDelivered: 1,
+		Stream:       m.stream,
+		Consumer:     m.consumer,
+		Timestamp:    time.Now(),
+	}, nil
+}
+
+func (m *memoryMsg) Data() []byte                           { return m.msg.Data }
+func (m *memoryMsg) Headers() nats.Header                   { return m.msg.Header }
+func (m *memoryMsg) Subject() string                        { return m.msg.Subject }
+func (m *memoryMsg) Reply() string                          { return "" }
+func (m *memoryMsg) Ack() error                             { return nil }
+func (m *memoryMsg) DoubleAck(context.Context) error        { return nil }
+func (m *memoryMsg) Nak() error                             { return nil }
+func (m *memoryMsg) NakWithDelay(delay time.Duration) error { return nil }
+func (m *memoryMsg) InProgress() error                      { return nil }
+func (m *memoryMsg) Term() error                            { return nil }
+func (m *memoryMsg) TermWithReason(reason string) error     { return nil }
+
+// memoryFuture is an already resolved asynchronous publish.
+type memoryFuture struct {
+	msg *nats.Msg
+	ok  chan *jetstream.PubAck
+	err chan error
+}
+
+func (f *memoryFuture) Ok() <-chan *jetstream.PubAck { return f.ok }
+func (f *memoryFuture) Err() <-chan error            { return f.err }
+func (f *memoryFuture) Msg() *nats.Msg               { return f.msg }
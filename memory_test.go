@@ -0,0 +1,165 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+// parityClients returns a JetStream client with a products stream next to an in-memory client,
+// so tests can check that both behave the same.
+func parityClients(t *testing.T) map[string]Client {
+	t.Helper()
+
+	jetStream := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, jetStream, "products", "product.>")
+
+	memory := NewInMemory(WithLogger(&testLogger{}))
+	t.Cleanup(memory.Close)
+
+	return map[string]Client{"jetstream": jetStream, "memory": memory}
+}
+
+func TestInMemoryPublishSubscribeParity(t *testing.T) {
+	for name, client := range parityClients(t) {
+		t.Run(name, func(t *testing.T) {
+			received := make(chan jetstream.Msg, 1)
+			var got *v1.ProductCreated
+			_, err := client.Subscribe(context.Background(), "product.created", "products", "parity_test", func() proto.Message { return &v1.ProductCreated{} },
+				func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+					got = msg.(*v1.ProductCreated)
+					received <- m
+					return m.Ack()
+				})
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+
+			ack, err := client.PublishMsg(context.Background(), &Message{
+				Subject: "product.created",
+				Proto:   &v1.ProductCreated{Id: "p-1"},
+				Headers: nats.Header{"Tenant": {"acme"}},
+			})
+			if err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+			if ack.Stream != "products" || ack.Sequence != 1 {
+				t.Errorf("ack = %+v, want sequence 1 on products", ack)
+			}
+
+			var m jetstream.Msg
+			select {
+			case m = <-received:
+			case <-time.After(5 * time.Second):
+				t.Fatal("message was not received")
+			}
+
+			if got.GetId() != "p-1" {
+				t.Errorf("id = %q, want p-1", got.GetId())
+			}
+			if m.Subject() != "product.created" {
+				t.Errorf("subject = %q, want product.created", m.Subject())
+			}
+			if m.Headers().Get("Tenant") != "acme" || m.Headers().Get(HeaderContentType) != "application/protobuf" {
+				t.Errorf("headers = %v, want the tenant and content type", m.Headers())
+			}
+
+			meta, err := m.Metadata()
+			if err != nil {
+				t.Fatalf("metadata: %v", err)
+			}
+			if meta.Stream != "products" || meta.Consumer != "parity_test" || meta.Sequence.Stream != 1 {
+				t.Errorf("metadata = %+v, want sequence 1 of parity_test on products", meta)
+			}
+		})
+	}
+}
+
+func TestInMemoryRequestReplyParity(t *testing.T) {
+	for name, client := range parityClients(t) {
+		t.Run(name, func(t *testing.T) {
+			err := client.Reply("greeter.hello", func() proto.Message { return &v1.SayHelloRequest{} },
+				func(ctx context.Context, req proto.Message) (proto.Message, error) {
+					name := req.(*v1.SayHelloRequest).GetName()
+					if name == "" {
+						return nil, &ServiceError{Code: "400", Description: "name is required"}
+					}
+					return &v1.SayHelloResponse{Message: "hello " + name}, nil
+				})
+			if err != nil {
+				t.Fatalf("reply: %v", err)
+			}
+
+			factory := func() proto.Message { return &v1.SayHelloResponse{} }
+
+			resp, err := client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{Name: "ada"}, factory, time.Second)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			if got := resp.(*v1.SayHelloResponse).GetMessage(); got != "hello ada" {
+				t.Errorf("message = %q, want hello ada", got)
+			}
+
+			_, err = client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{}, factory, time.Second)
+			var serviceErr *ServiceError
+			if !errors.As(err, &serviceErr) || serviceErr.Code != "400" || serviceErr.Description != "name is required" {
+				t.Errorf("request: got %v, want a 400 ServiceError", err)
+			}
+
+			_, err = client.Request(context.Background(), "greeter.goodbye", &v1.SayHelloRequest{Name: "ada"}, factory, time.Second)
+			if !errors.Is(err, nats.ErrNoResponders) {
+				t.Errorf("request without responders: got %v, want ErrNoResponders", err)
+			}
+		})
+	}
+}
+
+func TestInMemoryPublishAsyncEncodesOnce(t *testing.T) {
+	var encodes int
+	client := NewInMemory(WithLogger(&testLogger{}), WithValidators(func(subject string, msg proto.Message) error {
+		encodes++
+		return nil
+	}))
+	t.Cleanup(client.Close)
+
+	var received int
+	_, err := client.Subscribe(context.Background(), "product.created", "products", "async_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			received++
+			return m.Ack()
+		})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	future, err := client.PublishAsync(context.Background(), "product.created", &v1.ProductCreated{Id: "p-1"})
+	if err != nil {
+		t.Fatalf("publish async: %v", err)
+	}
+
+	select {
+	case ack := <-future.Ok():
+		if ack.Stream != "products" || ack.Sequence != 1 {
+			t.Errorf("ack = %+v, want sequence 1 on products", ack)
+		}
+	case err := <-future.Err():
+		t.Fatalf("publish async: %v", err)
+	}
+
+	if encodes != 1 {
+		t.Errorf("encodes = %d, want 1", encodes)
+	}
+	if received != 1 {
+		t.Errorf("received = %d, want 1", received)
+	}
+	if future.Msg().Header.Get(HeaderMessageType) == "" {
+		t.Errorf("future message headers = %v, want the message type", future.Msg().Header)
+	}
+}
@@ -0,0 +1,13 @@
+package rimnats
+
+import (
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+// Message is a protobuf message published with PublishMsg together with its NATS headers.
+type Message struct {
+	Subject string        // Subject the message is published to
+	Proto   proto.Message // Payload, encoded with the event codec
+	Headers nats.Header   // Headers sent alongside the payload, may be nil
+}
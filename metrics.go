@@ -0,0 +1,198 @@
+package rimnats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the Prometheus metrics reported by a Rimnats client.
+// It implements prometheus.Collector so it can be registered with an existing registry
+// and is passed to the client with WithMetrics. A nil *Metrics records nothing.
+type Metrics struct {
+	activeSubscriptions prometheus.Gauge         // Core NATS subscriptions currently active
+	activeConsumers     prometheus.Gauge         // JetStream consumers currently consuming
+	published           *prometheus.CounterVec   // Messages acknowledged by JetStream on publish
+	publishErrors       *prometheus.CounterVec   // Publishes that failed
+	publishDuration     *prometheus.HistogramVec // Time from publishing to the JetStream acknowledgement
+	consumed            *prometheus.CounterVec   // Messages delivered to subscription handlers
+	handlerErrors       *prometheus.CounterVec   // Messages whose decoding or handler failed
+	handlerDuration     *prometheus.HistogramVec // Time spent decoding and handling a message
+	acks                *prometheus.CounterVec   // Acknowledgments sent for consumed messages, by type
+}
+
+// NewMetrics creates the rimnats metric set.
+func NewMetrics() *Metrics {
+	labels := []string{"subject", "stream"}
+
+	return &Metrics{
+		activeSubscriptions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "rimnats_active_subscriptions",
+			Help: "Number of core NATS subscriptions (e.g. Reply handlers) currently active.",
+		}),
+		activeConsumers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "rimnats_active_consumers",
+			Help: "Number of JetStream consumers currently consuming messages.",
+		}),
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rimnats_messages_published_total",
+			Help: "Number of messages acknowledged by JetStream on publish.",
+		}, labels),
+		publishErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rimnats_publish_errors_total",
+			Help: "Number of publishes that failed.",
+		}, labels),
+		publishDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "rimnats_publish_duration_seconds",
+			Help:    "Time from publishing a message to its JetStream acknowledgement.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		consumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rimnats_messages_consumed_total",
+			Help: "Number of messages delivered to subscription handlers.",
+		}, labels),
+		handlerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rimnats_handler_errors_total",
+			Help: "Number of messages whose decoding or handler failed.",
+		}, labels),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "rimnats_handler_duration_seconds",
+			Help:    "Time spent decoding and handling a consumed message.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		acks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rimnats_acks_total",
+			Help: "Number of acknowledgments sent for consumed messages, by type (ack, nak, term, in_progress).",
+		}, append(labels, "type")),
+	}
+}
+
+// collectors lists every metric of the set.
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.activeSubscriptions,
+		m.activeConsumers,
+		m.published,
+		m.publishErrors,
+		m.publishDuration,
+		m.consumed,
+		m.handlerErrors,
+		m.handlerDuration,
+		m.acks,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// setActive updates the active subscription and consumer gauges.
+func (m *Metrics) setActive(subscriptions, consumers int) {
+	if m == nil {
+		return
+	}
+
+	m.activeSubscriptions.Set(float64(subscriptions))
+	m.activeConsumers.Set(float64(consumers))
+}
+
+// observePublished records a publish that took d and whether it failed.
+func (m *Metrics) observePublished(subject, stream string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	if err != nil {
+		m.publishErrors.WithLabelValues(subject, stream).Inc()
+		return
+	}
+
+	m.published.WithLabelValues(subject, stream).Inc()
+	m.publishDuration.WithLabelValues(subject, stream).Observe(d.Seconds())
+}
+
+// observeConsumed records a message whose handling took d and whether it failed.
+func (m *Metrics) observeConsumed(subject, stream string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.consumed.WithLabelValues(subject, stream).Inc()
+	m.handlerDuration.WithLabelValues(subject, stream).Observe(d.Seconds())
+	if err != nil {
+		m.handlerErrors.WithLabelValues(subject, stream).Inc()
+	}
+}
+
+// observeAck records an acknowledgment of type kind.
+func (m *Metrics) observeAck(subject, stream, kind string) {
+	if m == nil {
+		return
+	}
+
+	m.acks.WithLabelValues(subject, stream, kind).Inc()
+}
+
+// meteredMsg counts the acknowledgments sent for a consumed message, whether they are sent
+// by the handler or by rimnats itself.
+type meteredMsg struct {
+	jetstream.Msg
+	metrics *Metrics
+	stream  string
+}
+
+// Ack acknowledges the message and counts it.
+func (m *meteredMsg) Ack() error {
+	return m.observe("ack", m.Msg.Ack())
+}
+
+// DoubleAck acknowledges the message, waits for the server confirmation and counts it.
+func (m *meteredMsg) DoubleAck(ctx context.Context) error {
+	return m.observe("ack", m.Msg.DoubleAck(ctx))
+}
+
+// Nak negatively acknowledges the message and counts it.
+func (m *meteredMsg) Nak() error {
+	return m.observe("nak", m.Msg.Nak())
+}
+
+// NakWithDelay negatively acknowledges the message with a redelivery delay and counts it.
+func (m *meteredMsg) NakWithDelay(delay time.Duration) error {
+	return m.observe("nak", m.Msg.NakWithDelay(delay))
+}
+
+// InProgress resets the redelivery timer of the message and counts it.
+func (m *meteredMsg) InProgress() error {
+	return m.observe("in_progress", m.Msg.InProgress())
+}
+
+// Term terminates redelivery of the message and counts it.
+func (m *meteredMsg) Term() error {
+	return m.observe("term", m.Msg.Term())
+}
+
+// TermWithReason terminates redelivery of the message with a reason and counts it.
+func (m *meteredMsg) TermWithReason(reason string) error {
+	return m.observe("term", m.Msg.TermWithReason(reason))
+}
+
+// observe counts an acknowledgment of type kind that was sent successfully.
+func (m *meteredMsg) observe(kind string, err error) error {
+	if err == nil {
+		m.metrics.observeAck(m.Subject(), m.stream, kind)
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+package rimnats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+// gaugeValue returns the current value of gauge.
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	t.Helper()
+
+	var metric dto.Metric
+	if err := gauge.Write(&metric); err != nil {
+		t.Fatalf("read gauge: %v", err)
+	}
+
+	return metric.GetGauge().GetValue()
+}
+
+func TestActiveSubscriptionGauges(t *testing.T) {
+	metrics := NewMetrics()
+	client := newTestClient(t, rimnatstest.StartServer(t), WithMetrics(metrics))
+	createTestStream(t, client, "products", "product.>")
+
+	sub, err := client.Subscribe(context.Background(), "product.created", "products", "metrics_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error { return nil })
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	err = client.Reply("greeter.hello", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return &v1.SayHelloResponse{}, nil
+		})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	if got := gaugeValue(t, metrics.activeConsumers); got != 1 {
+		t.Errorf("active consumers = %v, want 1", got)
+	}
+	if got := gaugeValue(t, metrics.activeSubscriptions); got != 1 {
+		t.Errorf("active subscriptions = %v, want 1", got)
+	}
+
+	sub.Stop()
+	eventually(t, 5*time.Second, func() bool { return gaugeValue(t, metrics.activeConsumers) == 0 }, "active consumers did not drop to 0")
+
+	client.Close()
+	if got := gaugeValue(t, metrics.activeSubscriptions); got != 0 {
+		t.Errorf("active subscriptions after close = %v, want 0", got)
+	}
+}
@@ -0,0 +1,70 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// Middleware wraps a ProtoHandler with cross-cutting logic such as logging, authorization
+// or metrics. It is registered on the client with Use.
+type Middleware func(ProtoHandler) ProtoHandler
+
+// middlewareChain holds the middleware registered with Use.
+type middlewareChain struct {
+	mu          sync.RWMutex
+	middlewares []Middleware
+}
+
+// Use registers middlewares around every subscription handler. Middlewares run in the order
+// they are registered, the first one being the outermost, and apply to messages received
+// after the call, including those of existing subscriptions.
+func (n *rimNats) Use(middlewares ...Middleware) {
+	n.middleware.mu.Lock()
+	defer n.middleware.mu.Unlock()
+
+	n.middleware.middlewares = append(n.middleware.middlewares, middlewares...)
+}
+
+// wrap applies the registered middlewares around handler.
+func (c *middlewareChain) wrap(handler ProtoHandler) ProtoHandler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// Recovery returns a middleware that recovers from panics in the handler. The panic is logged
+// with its stack trace and returned as an error matching ErrHandlerPanic, so the message is
+// NAKed instead of the panic crashing the consumer. Handlers are always protected this way;
+// registering Recovery additionally recovers from panics in the middlewares after it.
+func Recovery(logger Logger) Middleware {
+	return func(next ProtoHandler) ProtoHandler {
+		return func(ctx context.Context, msg proto.Message, m jetstream.Msg) (err error) {
+			defer recoverPanic(logger, m.Subject(), &err)
+
+			return next(ctx, msg, m)
+		}
+	}
+}
+
+// recoverPanic recovers from a panic in a handler processing a message on subject, logging
+// it with its stack trace and storing an error matching ErrHandlerPanic in err. It must be
+// deferred directly.
+func recoverPanic(logger Logger, subject string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	logger.Error("💥 [ rimnats ]: handler panicked", "subject", subject, "panic", r, "stack", string(debug.Stack()))
+	*err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+}
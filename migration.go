@@ -0,0 +1,46 @@
+package rimnats
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// SubscribeRenamed consumes both the old and the new name of a subject that is being renamed,
+// routing messages from either to the same handler. A deprecation warning is logged for every
+// message still arriving on the old subject, so producers that have not migrated are easy to spot.
+// Once no more warnings are logged, switch to Subscribe with the new subject.
+//
+// Parameters:
+//   - oldSubject: The deprecated subject (may contain wildcards)
+//   - newSubject: The subject replacing it
+//   - stream: The stream holding both subjects
+//   - durable: The durable name for the subscription
+//   - factory: A function that creates new instances of the protobuf message type
+//   - handler: A function that processes decoded protobuf messages
+//   - opts: Optional subscription options
+//
+// Returns:
+//   - *Subscription: Handle used to stop the subscription
+//   - error: Returns an error if the subscription setup fails
+func (n *rimNats) SubscribeRenamed(
+	ctx context.Context,
+	oldSubject string,
+	newSubject string,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	handler ProtoHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	warnDeprecated := func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+		if subjectMatches(oldSubject, m.Subject()) {
+			n.loggR.Warn("⚠️ [ rimnats ]: message received on deprecated subject", "subject", m.Subject(), "replacement", newSubject)
+		}
+
+		return handler(ctx, msg, m)
+	}
+
+	return n.subscribe(ctx, []string{oldSubject, newSubject}, stream, durable, factory, warnDeprecated, opts...)
+}
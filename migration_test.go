@@ -0,0 +1,59 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSubscribeRenamed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	logger := &testLogger{}
+	client := newTestClient(t, rimnatstest.StartServer(t), WithLogger(logger))
+	createTestStream(t, client, "products", "product.>", "catalog.>")
+
+	var mu sync.Mutex
+	received := map[string]string{}
+	_, err := client.SubscribeRenamed(ctx, "product.created", "catalog.product.created", "products", "migration_test",
+		func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			mu.Lock()
+			received[m.Subject()] = msg.(*v1.ProductCreated).GetId()
+			mu.Unlock()
+			return m.Ack()
+		})
+	if err != nil {
+		t.Fatalf("subscribe renamed: %v", err)
+	}
+
+	if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: "old"}); err != nil {
+		t.Fatalf("publish old: %v", err)
+	}
+	if _, err := client.Publish(ctx, "catalog.product.created", &v1.ProductCreated{Id: "new"}); err != nil {
+		t.Fatalf("publish new: %v", err)
+	}
+
+	eventually(t, 5*time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, "handler did not receive both subjects")
+
+	if received["product.created"] != "old" || received["catalog.product.created"] != "new" {
+		t.Errorf("received = %v", received)
+	}
+	if !logger.contains("WARN", "subject=product.created") {
+		t.Error("no deprecation warning for the old subject")
+	}
+	if logger.contains("WARN", "subject=catalog.product.created") {
+		t.Error("deprecation warning logged for the new subject")
+	}
+}
@@ -0,0 +1,123 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies rimnats as the source of its spans.
+const instrumentationName = "github.com/rimdesk/rimnats-go"
+
+// Span attribute keys recorded by rimnats.
+const (
+	attrSystem    = "messaging.system"
+	attrOperation = "messaging.operation.name"
+	attrSubject   = "messaging.destination.name"
+	attrStream    = "messaging.nats.stream"
+	attrAck       = "messaging.nats.ack"
+)
+
+// WithObservability makes every publish, subscribe, request and reply path observable in one call.
+// Spans are recorded with tracerProvider and the client's Metrics are created and registered with
+// registerer. Either argument may be nil to leave that signal disabled. Logs keep going to the
+// client's Logger as structured key/value pairs.
+func WithObservability(tracerProvider trace.TracerProvider, registerer prometheus.Registerer) Option {
+	return func(cfg *nexorConfig) {
+		if tracerProvider != nil {
+			cfg.Tracer = tracerProvider.Tracer(instrumentationName)
+		}
+
+		if registerer != nil {
+			cfg.Metrics = NewMetrics()
+			cfg.registerer = registerer
+		}
+	}
+}
+
+// WithPropagator sets the propagator used to carry trace context in message headers.
+// Defaults to the global propagator returned by otel.GetTextMapPropagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Propagator = propagator
+	}
+}
+
+// propagator returns the propagator carrying trace context in message headers.
+func (n *rimNats) propagator() propagation.TextMapPropagator {
+	if n.cfg.Propagator != nil {
+		return n.cfg.Propagator
+	}
+
+	return otel.GetTextMapPropagator()
+}
+
+// headerCarrier adapts NATS headers to the OpenTelemetry propagation carrier.
+// Unlike propagation.HeaderCarrier it keeps keys as written, since NATS headers are case-sensitive.
+type headerCarrier nats.Header
+
+// Get returns the first value of key.
+func (c headerCarrier) Get(key string) string {
+	return nats.Header(c).Get(key)
+}
+
+// Set sets key to value.
+func (c headerCarrier) Set(key, value string) {
+	nats.Header(c).Set(key, value)
+}
+
+// Keys lists the header keys.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// registerMetrics registers the client metrics with the registerer set by WithObservability.
+// When equivalent metrics are already registered, e.g. by another client, those are reused.
+func (n *rimNats) registerMetrics() {
+	if n.cfg.registerer == nil || n.cfg.Metrics == nil {
+		return
+	}
+
+	err := n.cfg.registerer.Register(n.cfg.Metrics)
+
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		if existing, ok := registered.ExistingCollector.(*Metrics); ok {
+			n.cfg.Metrics = existing
+			return
+		}
+	}
+
+	if err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to register metrics", "error", err)
+	}
+}
+
+// startSpan starts a span for operation op on subject.
+func (n *rimNats) startSpan(ctx context.Context, op, subject string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String(attrSystem, "nats"),
+		attribute.String(attrOperation, op),
+		attribute.String(attrSubject, subject),
+	)
+
+	return n.cfg.Tracer.Start(ctx, op+" "+subject, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// recordSpanError marks span as failed with err.
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,87 @@
+package rimnats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestObservabilityRecordsSpansAndMetrics(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	registry := prometheus.NewRegistry()
+
+	client := newTestClient(t, rimnatstest.StartServer(t), WithObservability(tracerProvider, registry),
+		WithPropagator(propagation.TraceContext{}))
+	createTestStream(t, client, "products", "product.>")
+
+	handled := make(chan struct{}, 1)
+	_, err := client.Subscribe(context.Background(), "product.created", "products", "observability_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			handled <- struct{}{}
+			return m.Ack()
+		})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	if _, err := client.Publish(context.Background(), "product.created", &v1.ProductCreated{Id: "p-1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not handled")
+	}
+
+	metrics := client.cfg.Metrics
+	eventually(t, 5*time.Second, func() bool {
+		return testutil.ToFloat64(metrics.acks.WithLabelValues("product.created", "products", "ack")) == 1
+	}, "ack was not counted")
+	if got := testutil.ToFloat64(metrics.published.WithLabelValues("product.created", "products")); got != 1 {
+		t.Errorf("published = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.consumed.WithLabelValues("product.created", "products")); got != 1 {
+		t.Errorf("consumed = %v, want 1", got)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	if len(families) == 0 {
+		t.Error("no metrics registered with the registerer")
+	}
+
+	var publish, process sdktrace.ReadOnlySpan
+	eventually(t, 5*time.Second, func() bool {
+		for _, span := range recorder.Ended() {
+			switch span.Name() {
+			case "publish product.created":
+				publish = span
+			case "process product.created":
+				process = span
+			}
+		}
+		return publish != nil && process != nil
+	}, "publish and process spans were not recorded")
+
+	if process.SpanKind() != trace.SpanKindConsumer {
+		t.Errorf("process span kind = %v, want consumer", process.SpanKind())
+	}
+	if process.SpanContext().TraceID() != publish.SpanContext().TraceID() {
+		t.Error("process span is not in the publish span's trace")
+	}
+}
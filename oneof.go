@@ -0,0 +1,72 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SubscribeOneof subscribes to envelopes carrying one of several payloads in a oneof field and
+// dispatches each message to the handler registered for the field that is set. Message payloads
+// are passed to the handler unwrapped; scalar fields pass the whole envelope. Messages without a
+// matching handler fail with ErrNoHandler and are terminated, so they are not redelivered.
+//
+// Parameters:
+//   - subject: The NATS subject to subscribe to
+//   - stream: The stream name for the subscription
+//   - durable: The durable name for the subscription
+//   - factory: A function that creates new instances of the envelope message type
+//   - handlers: Handlers keyed by the name of the oneof field they process
+//   - opts: Optional subscription options
+//
+// Returns:
+//   - *Subscription: Handle used to stop the subscription
+//   - error: Returns an error if the subscription setup fails
+func (n *rimNats) SubscribeOneof(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	handlers map[protoreflect.Name]ProtoHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return n.Subscribe(ctx, subject, stream, durable, factory, dispatchOneof(handlers), opts...)
+}
+
+// dispatchOneof returns a handler routing envelopes to handlers by their populated oneof field.
+func dispatchOneof(handlers map[protoreflect.Name]ProtoHandler) ProtoHandler {
+	return func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+		envelope := msg.ProtoReflect()
+		oneofs := envelope.Descriptor().Oneofs()
+
+		for i := 0; i < oneofs.Len(); i++ {
+			oneof := oneofs.Get(i)
+			if oneof.IsSynthetic() {
+				continue
+			}
+
+			field := envelope.WhichOneof(oneof)
+			if field == nil {
+				continue
+			}
+
+			handler, ok := handlers[field.Name()]
+			if !ok {
+				continue
+			}
+
+			payload := msg
+			if field.Message() != nil {
+				payload = envelope.Get(field).Message().Interface()
+			}
+
+			return handler(ctx, payload, m)
+		}
+
+		return fmt.Errorf("%w: %s", ErrNoHandler, envelope.Descriptor().FullName())
+	}
+}
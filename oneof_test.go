@@ -0,0 +1,73 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestSubscribeOneof(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "values", "value.>")
+
+	var mu sync.Mutex
+	var texts []string
+	var structs []*structpb.Struct
+	handlers := map[protoreflect.Name]ProtoHandler{
+		// Scalar fields receive the whole envelope
+		"string_value": func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			mu.Lock()
+			defer mu.Unlock()
+			texts = append(texts, msg.(*structpb.Value).GetStringValue())
+			return nil
+		},
+		// Message fields receive the unwrapped payload
+		"struct_value": func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			mu.Lock()
+			defer mu.Unlock()
+			structs = append(structs, msg.(*structpb.Struct))
+			return nil
+		},
+	}
+
+	sub, err := client.SubscribeOneof(ctx, "value.set", "values", "oneof_test", func() proto.Message { return &structpb.Value{} }, handlers, WithAutoAck())
+	if err != nil {
+		t.Fatalf("subscribe oneof: %v", err)
+	}
+
+	payload, _ := structpb.NewStruct(map[string]any{"name": "chair"})
+	for _, msg := range []*structpb.Value{structpb.NewStringValue("hello"), structpb.NewStructValue(payload), structpb.NewNumberValue(42)} {
+		if _, err := client.Publish(ctx, "value.set", msg); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	// The number value has no handler and is terminated instead of redelivered
+	consumer, err := client.JetStream().Consumer(ctx, "values", sub.Consumer())
+	if err != nil {
+		t.Fatalf("consumer: %v", err)
+	}
+	eventually(t, 5*time.Second, func() bool {
+		info, err := consumer.Info(ctx)
+		return err == nil && info.AckFloor.Stream == 3 && info.NumAckPending == 0
+	}, "messages were not all settled")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(texts) != 1 || texts[0] != "hello" {
+		t.Errorf("string handler got %v, want [hello]", texts)
+	}
+	if len(structs) != 1 || structs[0].GetFields()["name"].GetStringValue() != "chair" {
+		t.Errorf("struct handler got %v, want one struct named chair", structs)
+	}
+}
@@ -0,0 +1,194 @@
+package rimnats
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"golang.org/x/time/rate"
+)
+
+// Option configures a Rimnats client at construction time.
+type Option func(*nexorConfig)
+
+// WithNatsOptions appends raw NATS connection options used when connecting to the server.
+func WithNatsOptions(opts ...nats.Option) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Opts = append(cfg.Opts, opts...)
+	}
+}
+
+// WithEventCodec sets the codec used by the event path (Publish and Subscribe).
+// Defaults to ProtoCodec.
+func WithEventCodec(codec Codec) Option {
+	return func(cfg *nexorConfig) {
+		cfg.EventCodec = codec
+	}
+}
+
+// WithRPCCodec sets the codec used by the RPC path (Request and Reply).
+// Defaults to ProtoCodec.
+func WithRPCCodec(codec Codec) Option {
+	return func(cfg *nexorConfig) {
+		cfg.RPCCodec = codec
+	}
+}
+
+// WithOutbox buffers up to size publishes made before Connect or while the connection is down
+// and replays them once it is established. Publish returns nil for buffered messages.
+func WithOutbox(size int) Option {
+	return func(cfg *nexorConfig) {
+		cfg.OutboxSize = size
+	}
+}
+
+// WithOutboxTTL drops buffered publishes older than ttl instead of replaying them after
+// a reconnect, so very stale events are not delivered after an extended outage.
+func WithOutboxTTL(ttl time.Duration) Option {
+	return func(cfg *nexorConfig) {
+		cfg.OutboxTTL = ttl
+	}
+}
+
+// WithMetrics reports client metrics to m. Register m with a Prometheus registry to expose them.
+func WithMetrics(m *Metrics) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Metrics = m
+	}
+}
+
+// WithPublishWaitForConnection makes Publish wait up to maxWait for a lost connection to
+// recover before publishing. If the connection is still down afterwards Publish returns
+// ErrDisconnected, or buffers the message when WithOutbox is enabled.
+func WithPublishWaitForConnection(maxWait time.Duration) Option {
+	return func(cfg *nexorConfig) {
+		cfg.PublishWait = maxWait
+	}
+}
+
+// WithLogger routes client logs to logger instead of the default Beego console logger.
+func WithLogger(logger Logger) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Logger = logger
+	}
+}
+
+// WithFactoryRegistry uses registry for RegisterFactory and SubscribeRegistered, allowing
+// several clients to share the same registrations. Each client has its own registry by default.
+func WithFactoryRegistry(registry *FactoryRegistry) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Factories = registry
+	}
+}
+
+// WithTLS connects over mutual TLS, presenting the client certificate in certFile and keyFile
+// and verifying the server against the CA bundle in caFile. Either the certificate pair or
+// caFile may be empty to only verify the server or only present a client certificate.
+func WithTLS(certFile, keyFile, caFile string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.TLSCertFile = certFile
+		cfg.TLSKeyFile = keyFile
+		cfg.TLSCAFile = caFile
+	}
+}
+
+// WithTLSConfig connects over TLS using config, which takes precedence over the files set with WithTLS.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(cfg *nexorConfig) {
+		cfg.TLSConfig = config
+	}
+}
+
+// WithCredsFile authenticates with the NATS credentials (.creds) file at path, as required by
+// managed providers such as Synadia Cloud. Defaults to the RIMNATS.CREDS_FILE environment variable.
+func WithCredsFile(path string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.CredsFile = path
+	}
+}
+
+// WithToken authenticates with token.
+func WithToken(token string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Token = token
+	}
+}
+
+// WithNKey authenticates with the NKey seed stored in seedFile.
+func WithNKey(seedFile string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.NKeySeedFile = seedFile
+	}
+}
+
+// WithUserPassword authenticates with user and password.
+func WithUserPassword(user, password string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.User = user
+		cfg.Password = password
+	}
+}
+
+// WithInboxPrefix replaces the "_INBOX" prefix of the inboxes receiving request replies, e.g.
+// with "_INBOX.tenant-a", so accounts shared by several tenants can grant each tenant
+// permission to subscribe to its own inbox subtree only.
+func WithInboxPrefix(prefix string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.InboxPrefix = prefix
+	}
+}
+
+// WithDryRun makes every Publish validate the message without sending it, see WithPublishDryRun.
+func WithDryRun() Option {
+	return func(cfg *nexorConfig) {
+		cfg.DryRun = true
+	}
+}
+
+// WithValidators runs validators on every message before it is encoded and published.
+// A failing validator aborts the publish with its error.
+func WithValidators(validators ...Validator) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Validators = append(cfg.Validators, validators...)
+	}
+}
+
+// WithConnectionPool spreads Publish and PublishAsync round-robin across size connections,
+// each with its own JetStream context, capped at the configured maximum connections
+// (RIMNATS.MAX_CONNECTIONS). Subscriptions, requests and stream management stay on the
+// primary connection.
+//
+// A single connection multiplexes any number of goroutines and is enough for most
+// publishers. A pool helps when one connection's write loop or socket becomes the
+// bottleneck, e.g. thousands of concurrent publishers of small messages on a multi-core
+// host. Messages published through different connections may be stored out of order.
+func WithConnectionPool(size int) Option {
+	return func(cfg *nexorConfig) {
+		cfg.PoolSize = size
+	}
+}
+
+// WithPublishRateLimit limits publishes on subject, which may contain wildcards, to perSecond
+// messages per second with bursts of up to perSecond messages. Every subject matching a
+// wildcard shares the same budget. Publish and PublishAsync wait for the budget to allow
+// the message and fail with ErrRateLimited when ctx is done, or its deadline would pass,
+// before it does. A perSecond of zero or less leaves subject unlimited.
+func WithPublishRateLimit(subject string, perSecond int) Option {
+	return func(cfg *nexorConfig) {
+		if perSecond <= 0 {
+			return
+		}
+
+		limiter := rate.NewLimiter(rate.Limit(perSecond), perSecond)
+		cfg.RateLimits = append(cfg.RateLimits, publishLimit{subject: subject, limiter: limiter})
+	}
+}
+
+// WithJetStreamDomain binds the JetStream context to domain, so publishes, consumers and
+// stream management target that domain, e.g. the hub domain from a leaf node. Equivalent to
+// Config.JetStreamDomain.
+func WithJetStreamDomain(domain string) Option {
+	return func(cfg *nexorConfig) {
+		cfg.Domain = domain
+	}
+}
@@ -0,0 +1,101 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// outboxEntry is a publish that was buffered while the connection was down.
+type outboxEntry struct {
+	msg      *nats.Msg              // Encoded message with its subject and headers
+	opts     []jetstream.PublishOpt // Publish options supplied by the caller
+	queuedAt time.Time              // Time the message entered the outbox
+}
+
+// outbox buffers publishes made before Connect or while disconnected and replays them once
+// the connection is established. Entries older than the TTL are dropped.
+type outbox struct {
+	mu      sync.Mutex
+	entries []outboxEntry
+	size    int              // Maximum number of buffered messages
+	ttl     time.Duration    // Maximum age of a buffered message, zero means no limit
+	now     func() time.Time // Clock used to age entries
+}
+
+func newOutbox(size int, ttl time.Duration, now func() time.Time) *outbox {
+	return &outbox{size: size, ttl: ttl, now: now}
+}
+
+// push buffers a message, returning ErrOutboxFull when the outbox is at capacity.
+func (o *outbox) push(msg *nats.Msg, opts []jetstream.PublishOpt) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.entries) >= o.size {
+		return ErrOutboxFull
+	}
+
+	o.entries = append(o.entries, outboxEntry{
+		msg:      msg,
+		opts:     opts,
+		queuedAt: o.now(),
+	})
+
+	return nil
+}
+
+// requeue puts entries that could not be replayed back at the front of the outbox.
+func (o *outbox) requeue(entries []outboxEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.entries = append(entries, o.entries...)
+}
+
+// take empties the outbox, returning the entries still within the TTL
+// and the number of stale entries that were dropped.
+func (o *outbox) take() ([]outboxEntry, int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries := o.entries
+	o.entries = nil
+
+	if o.ttl <= 0 {
+		return entries, 0
+	}
+
+	now := o.now()
+	fresh := entries[:0]
+	for _, entry := range entries {
+		if now.Sub(entry.queuedAt) <= o.ttl {
+			fresh = append(fresh, entry)
+		}
+	}
+
+	return fresh, len(entries) - len(fresh)
+}
+
+// replayOutbox publishes buffered messages once connected, dropping those older than the TTL.
+func (n *rimNats) replayOutbox(_ *nats.Conn) {
+	entries, dropped := n.outbox.take()
+	if dropped > 0 {
+		n.loggR.Warn("⏳ [ rimnats ]: dropped stale buffered messages", "count", dropped, "ttl", n.outbox.ttl)
+	}
+
+	for i, entry := range entries {
+		if _, err := n.js.PublishMsg(context.Background(), entry.msg, entry.opts...); err != nil {
+			n.loggR.Error("❌ [ rimnats ]: failed to replay buffered message", "subject", entry.msg.Subject, "error", err)
+			n.outbox.requeue(entries[i:])
+			return
+		}
+	}
+
+	if n.cfg.Debug && len(entries) > 0 {
+		n.loggR.Info("🚀 [ rimnats ]: replayed buffered messages", "count", len(entries))
+	}
+}
@@ -0,0 +1,114 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+// refusingDialer dials the server unless refuse is set, so tests can keep a client
+// disconnected for as long as they need.
+type refusingDialer struct {
+	refuse atomic.Bool
+}
+
+// Dial dials address, or fails while refuse is set.
+func (d *refusingDialer) Dial(network, address string) (net.Conn, error) {
+	if d.refuse.Load() {
+		return nil, errors.New("connection refused by test")
+	}
+
+	return net.Dial(network, address)
+}
+
+func TestOutboxReplaysAfterReconnect(t *testing.T) {
+	ctx := context.Background()
+	dialer := &refusingDialer{}
+	logger := &testLogger{}
+	now := time.Now()
+
+	client := newTestClient(t, rimnatstest.StartServer(t), WithLogger(logger), WithNatsOptions(nats.SetCustomDialer(dialer), nats.ReconnectWait(10*time.Millisecond)), WithOutbox(10), WithOutboxTTL(time.Minute))
+	client.outbox.now = func() time.Time { return now }
+	stream := createTestStream(t, client, "products", "product.>")
+
+	// Messages published while reconnecting are buffered
+	dialer.refuse.Store(true)
+	if err := client.conn.ForceReconnect(); err != nil {
+		t.Fatalf("force reconnect: %v", err)
+	}
+
+	if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: "stale"}); err != nil {
+		t.Fatalf("publish stale: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: "fresh"}); err != nil {
+		t.Fatalf("publish fresh: %v", err)
+	}
+
+	dialer.refuse.Store(false)
+	eventually(t, 5*time.Second, client.conn.IsConnected, "client did not reconnect")
+
+	eventually(t, 5*time.Second, func() bool {
+		info, err := stream.Info(ctx)
+		return err == nil && info.State.Msgs > 0
+	}, "buffered message was not replayed")
+
+	if !logger.contains("WARN", "dropped stale buffered messages") {
+		t.Error("dropping the stale message was not logged")
+	}
+
+	info, err := stream.Info(ctx)
+	if err != nil {
+		t.Fatalf("stream info: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Fatalf("stream holds %d messages, want 1", info.State.Msgs)
+	}
+
+	raw, err := stream.GetLastMsgForSubject(ctx, "product.created")
+	if err != nil {
+		t.Fatalf("get last message: %v", err)
+	}
+	var msg v1.ProductCreated
+	if err := proto.Unmarshal(raw.Data, &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if msg.GetId() != "fresh" {
+		t.Errorf("replayed message id = %q, want fresh", msg.GetId())
+	}
+}
+
+func TestOutboxReplaysAfterConnect(t *testing.T) {
+	ctx := context.Background()
+	url := rimnatstest.StartServer(t)
+	stream := createTestStream(t, newTestClient(t, url), "products", "product.>")
+
+	client := New(url, WithLogger(&testLogger{}), WithOutbox(10)).(*rimNats)
+	t.Cleanup(client.Close)
+
+	// Publishing before Connect buffers the message
+	if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: "early"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if err := client.Connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	info, err := stream.Info(ctx)
+	if err != nil {
+		t.Fatalf("stream info: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("stream holds %d messages, want the buffered one", info.State.Msgs)
+	}
+}
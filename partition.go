@@ -0,0 +1,81 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Partition returns the partition of key among partitions, in the range [0, partitions). The
+// same key always maps to the same partition for a given number of partitions.
+func Partition(key string, partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// PartitionSubject returns the subject carrying events for key when subject is split into
+// partitions, e.g. "orders.3" for subject "orders". Publishing every event of a key to its
+// partition subject, and consuming each partition with SubscribePartition, keeps the events
+// of a key in order while the partitions are processed in parallel.
+func PartitionSubject(subject, key string, partitions int) string {
+	return partitionSubject(subject, Partition(key, partitions))
+}
+
+// partitionSubject returns the subject of the given partition of subject.
+func partitionSubject(subject string, partition int) string {
+	return subject + "." + strconv.Itoa(partition)
+}
+
+// SubscribePartition subscribes to one partition of subject, as computed by PartitionSubject.
+// Each partition gets its own consumer, named after durable with the partition appended
+// (e.g. "billing-3"), so every partition is consumed in order by a single subscriber while
+// different partitions can run on different instances. The stream must capture the partition
+// subjects, e.g. with "orders.*".
+//
+// Parameters:
+//   - subject: The partitioned subject, without the partition suffix
+//   - partition: The partition to consume, in the range [0, partitions)
+//   - stream: The stream name for the subscription
+//   - durable: The base durable name, empty for an ephemeral consumer
+//   - factory: A function that creates new instances of the protobuf message type
+//   - handler: A function that processes decoded protobuf messages
+//   - opts: Optional subscription options
+//
+// Returns:
+//   - *Subscription: Handle used to stop the subscription
+//   - error: Returns an error if partition is negative or the subscription setup fails
+func (n *rimNats) SubscribePartition(
+	ctx context.Context,
+	subject string,
+	partition int,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	handler ProtoHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	if partition < 0 {
+		return nil, wrapError("subscribe", stream, subject, errors.New("negative partition"))
+	}
+
+	return n.Subscribe(ctx, partitionSubject(subject, partition), stream, partitionDurable(durable, partition), factory, handler, opts...)
+}
+
+// partitionDurable returns the durable name of the consumer of partition, empty for an
+// ephemeral consumer.
+func partitionDurable(durable string, partition int) string {
+	if durable == "" {
+		return ""
+	}
+
+	return durable + "-" + strconv.Itoa(partition)
+}
@@ -0,0 +1,42 @@
+package rimnats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// PauseConsumer pauses the consumer durable on stream until until, e.g. for a downstream
+// maintenance window. The consumer and its position are kept: subscriptions stay running
+// but receive nothing while paused, then continue where they left off. Messages published
+// meanwhile show up as pending in ConsumerInfo and PendingMessages. Requires NATS server
+// 2.11 or newer.
+func (n *rimNats) PauseConsumer(ctx context.Context, stream, durable string, until time.Time) (*jetstream.ConsumerPauseResponse, error) {
+	resp, err := n.js.PauseConsumer(ctx, stream, durable, until)
+	if err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to pause consumer", "stream", stream, "consumer", durable, "error", err)
+		return nil, wrapError("pause consumer", stream, "", err)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("⏸️ [ rimnats ]: consumer paused", "stream", stream, "consumer", durable, "until", resp.PauseUntil)
+	}
+
+	return resp, nil
+}
+
+// ResumeConsumer resumes the consumer durable on stream before its pause expires.
+func (n *rimNats) ResumeConsumer(ctx context.Context, stream, durable string) (*jetstream.ConsumerPauseResponse, error) {
+	resp, err := n.js.ResumeConsumer(ctx, stream, durable)
+	if err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to resume consumer", "stream", stream, "consumer", durable, "error", err)
+		return nil, wrapError("resume consumer", stream, "", err)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("▶️ [ rimnats ]: consumer resumed", "stream", stream, "consumer", durable)
+	}
+
+	return resp, nil
+}
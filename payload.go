@@ -0,0 +1,45 @@
+package rimnats
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// PayloadTooLargeError is returned by Publish when the encoded message exceeds the maximum
+// payload size negotiated with the server. It matches ErrPayloadTooLarge with errors.Is.
+type PayloadTooLargeError struct {
+	Size int64 // Size of the encoded payload and headers in bytes
+	Max  int64 // Maximum payload size accepted by the server in bytes
+}
+
+// Error returns the actual and maximum payload sizes.
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("rimnats: payload too large: %d bytes exceeds the server maximum of %d bytes", e.Size, e.Max)
+}
+
+// Is reports whether target is ErrPayloadTooLarge.
+func (e *PayloadTooLargeError) Is(target error) bool {
+	return target == ErrPayloadTooLarge
+}
+
+// checkPayloadSize returns a *PayloadTooLargeError when msg does not fit in the maximum payload
+// of the connection. Messages are not checked before the client has connected.
+func (n *rimNats) checkPayloadSize(msg *nats.Msg) error {
+	if n.conn == nil {
+		return nil
+	}
+
+	limit := n.conn.MaxPayload()
+	if limit <= 0 {
+		return nil
+	}
+
+	// The server limit covers the headers and the data, not the subject
+	size := int64(msg.Size() - len(msg.Subject) - len(msg.Reply))
+	if size > limit {
+		return &PayloadTooLargeError{Size: size, Max: limit}
+	}
+
+	return nil
+}
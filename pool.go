@@ -0,0 +1,95 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// connPool holds the additional connections publishes are spread across when
+// WithConnectionPool is set. Subscriptions, requests and stream management keep using the
+// client's primary connection.
+type connPool struct {
+	conns []*nats.Conn          // Pooled connections, the primary connection first
+	js    []jetstream.JetStream // JetStream context of each pooled connection
+	next  atomic.Uint64         // Counter selecting the next connection round-robin
+}
+
+// connectPool opens size-1 connections next to the primary one, each with its own JetStream
+// context.
+func (n *rimNats) connectPool(size int, opts []nats.Option) (*connPool, error) {
+	pool := &connPool{conns: []*nats.Conn{n.conn}, js: []jetstream.JetStream{n.js}}
+
+	for i := 1; i < size; i++ {
+		connOpts := append(append([]nats.Option(nil), opts...), nats.Name(fmt.Sprintf("%s-%d", n.cfg.ClientName, i)))
+
+		conn, err := nats.Connect(n.cfg.Url, connOpts...)
+		if err != nil {
+			pool.close()
+			return nil, err
+		}
+
+		js, err := n.newJetStream(conn)
+		if err != nil {
+			conn.Close()
+			pool.close()
+			return nil, err
+		}
+
+		pool.conns = append(pool.conns, conn)
+		pool.js = append(pool.js, js)
+	}
+
+	return pool, nil
+}
+
+// pick returns the JetStream context of the next connection.
+func (p *connPool) pick() jetstream.JetStream {
+	return p.js[(p.next.Add(1)-1)%uint64(len(p.js))]
+}
+
+// publishAsyncComplete returns a channel closed once the asynchronous publishes of every
+// pooled connection have completed.
+func (p *connPool) publishAsyncComplete() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for _, js := range p.js {
+			<-js.PublishAsyncComplete()
+		}
+		close(done)
+	}()
+
+	return done
+}
+
+// flush flushes every pooled connection but the primary one.
+func (p *connPool) flush(ctx context.Context) error {
+	for _, conn := range p.conns[1:] {
+		if err := flushConn(ctx, conn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// close closes every pooled connection but the primary one, which the client closes itself.
+func (p *connPool) close() {
+	for _, conn := range p.conns[1:] {
+		if !conn.IsClosed() {
+			conn.Close()
+		}
+	}
+}
+
+// publisher returns the JetStream context the next publish goes through.
+func (n *rimNats) publisher() jetstream.JetStream {
+	if n.pool != nil {
+		return n.pool.pick()
+	}
+
+	return n.js
+}
@@ -0,0 +1,150 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// Priority is the processing priority of a message. Each priority is published
+// to its own subject so consumers can favour higher priorities.
+type Priority int
+
+const (
+	PriorityLow    Priority = iota // Processed after every other priority
+	PriorityNormal                 // Processed after high priority messages
+	PriorityHigh                   // Processed first
+)
+
+// priorities lists the priority levels from highest to lowest.
+var priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// String returns the subject token used for the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityLow:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+// PrioritySubject returns the subject used for messages of the given priority, e.g. "work.high".
+func PrioritySubject(subject string, priority Priority) string {
+	return subject + "." + priority.String()
+}
+
+// PriorityPublisher publishes messages to the per-priority subjects derived from a base subject.
+// The stream must capture every priority subject, e.g. by binding "work.*".
+type PriorityPublisher struct {
+	client  Client // Client used to publish
+	subject string // Base subject the priority token is appended to
+}
+
+// NewPriorityPublisher creates a publisher for the priority subjects under subject.
+func NewPriorityPublisher(client Client, subject string) *PriorityPublisher {
+	return &PriorityPublisher{client: client, subject: subject}
+}
+
+// Publish publishes msg on the subject matching its priority.
+func (p *PriorityPublisher) Publish(ctx context.Context, priority Priority, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	return p.client.Publish(ctx, PrioritySubject(p.subject, priority), msg, opts...)
+}
+
+// PriorityConsumer consumes the per-priority subjects published by a PriorityPublisher.
+// Every round it fetches up to Weights[p] messages per priority, starting with the highest,
+// so high priority messages are handled first while lower priorities are not starved.
+type PriorityConsumer struct {
+	Weights  map[Priority]int // Maximum messages fetched per priority each round
+	IdleWait time.Duration    // Time to wait before polling again when every priority is empty
+
+	client  *rimNats
+	stream  string
+	subject string
+	durable string
+	factory func() proto.Message
+	handler ProtoHandler
+	options *subscribeOptions
+}
+
+// NewPriorityConsumer creates a consumer for the priority subjects under subject. A durable
+// consumer named "<durable>_<priority>" is created on the stream for every priority level.
+// The subscription options apply to each of them and to the messages they deliver.
+func NewPriorityConsumer(client Client, stream, subject, durable string, factory func() proto.Message, handler ProtoHandler, opts ...SubscribeOption) *PriorityConsumer {
+	return &PriorityConsumer{
+		Weights: map[Priority]int{
+			PriorityHigh:   10,
+			PriorityNormal: 5,
+			PriorityLow:    1,
+		},
+		IdleWait: 100 * time.Millisecond,
+		client:   client.GetEngine(),
+		stream:   stream,
+		subject:  subject,
+		durable:  durable,
+		factory:  factory,
+		handler:  handler,
+		options:  newSubscribeOptions(opts),
+	}
+}
+
+// Run consumes messages until ctx is cancelled. Handlers are responsible for acknowledging
+// messages unless WithAutoAck is set; messages that fail to decode or whose handler returns
+// an error are NAKed. It returns ErrNotSupported for clients without a JetStream connection,
+// such as the in-memory client.
+func (c *PriorityConsumer) Run(ctx context.Context) error {
+	if c.client == nil || c.client.js == nil {
+		return wrapError("priority consume", c.stream, c.subject, ErrNotSupported)
+	}
+
+	consumers := make(map[Priority]jetstream.Consumer, len(priorities))
+	for _, priority := range priorities {
+		subject := PrioritySubject(c.subject, priority)
+		_, consumer, err := c.client.createConsumer(ctx, "priority consume", []string{subject}, c.stream, c.durable+"_"+priority.String(), c.options)
+		if err != nil {
+			return err
+		}
+
+		consumers[priority] = consumer
+	}
+
+	for ctx.Err() == nil {
+		handled := 0
+		for _, priority := range priorities {
+			weight := c.Weights[priority]
+			if weight <= 0 {
+				continue
+			}
+
+			batch, err := consumers[priority].FetchNoWait(weight)
+			if err != nil {
+				return wrapError("priority consume", c.stream, PrioritySubject(c.subject, priority), err)
+			}
+
+			for m := range batch.Messages() {
+				c.client.handle(ctx, m, c.factory, c.handler, c.options)
+				handled++
+			}
+
+			if err := batch.Error(); err != nil && !errors.Is(err, jetstream.ErrNoMessages) {
+				return wrapError("priority consume", c.stream, PrioritySubject(c.subject, priority), err)
+			}
+		}
+
+		if handled > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.IdleWait):
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPriorityConsumerHandlesHighFirst(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "work", "work.*")
+
+	// Low priority work is published first
+	publisher := NewPriorityPublisher(client, "work")
+	for _, priority := range []Priority{PriorityLow, PriorityLow, PriorityNormal, PriorityNormal, PriorityHigh, PriorityHigh} {
+		if _, err := publisher.Publish(ctx, priority, &v1.ProductCreated{Id: priority.String()}); err != nil {
+			t.Fatalf("publish %s: %v", priority, err)
+		}
+	}
+
+	var mu sync.Mutex
+	var order []string
+	consumer := NewPriorityConsumer(client, "work", "work", "priority_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			mu.Lock()
+			order = append(order, msg.(*v1.ProductCreated).GetId())
+			if len(order) == 6 {
+				cancel()
+			}
+			mu.Unlock()
+			return nil
+		}, WithAutoAck())
+	consumer.Weights = map[Priority]int{PriorityHigh: 2, PriorityNormal: 2, PriorityLow: 2}
+
+	if err := consumer.Run(ctx); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"high", "high", "normal", "normal", "low", "low"}
+	if len(order) != len(want) {
+		t.Fatalf("handled %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("handled %v, want %v", order, want)
+		}
+	}
+}
+
+func TestPriorityConsumerInMemory(t *testing.T) {
+	consumer := NewPriorityConsumer(NewInMemory(), "work", "work", "priority_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error { return nil })
+
+	if err := consumer.Run(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("run: got %v, want ErrNotSupported", err)
+	}
+}
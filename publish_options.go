@@ -0,0 +1,89 @@
+package rimnats
+
+import (
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// PublishOption configures a single Publish call.
+type PublishOption func(*publishOptions)
+
+// publishOptions holds the per-publish settings applied by PublishOption.
+type publishOptions struct {
+	jsOpts        []jetstream.PublishOpt       // Options passed to the JetStream publish
+	encode        func([]byte) ([]byte, error) // Transform applied to the encoded payload, nil when disabled
+	capacity      *capacityWarning             // Stream usage check run after publishing, nil when disabled
+	codec         Codec                        // Codec used to encode the message, nil for the client's event codec
+	retry         *RetryPolicy                 // Policy retrying transient publish failures, nil to publish once
+	schemaVersion string                       // Schema version sent in the HeaderSchemaVersion header, empty to omit it
+	dryRun        bool                         // Validate the message without publishing it
+}
+
+// newPublishOptions applies opts on top of the default publish settings.
+func newPublishOptions(opts []PublishOption) *publishOptions {
+	options := &publishOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// WithPublishOpts passes options such as jetstream.WithExpectStream through to the JetStream publish.
+func WithPublishOpts(opts ...jetstream.PublishOpt) PublishOption {
+	return func(o *publishOptions) {
+		o.jsOpts = append(o.jsOpts, opts...)
+	}
+}
+
+// WithPayloadEncode transforms the encoded payload before it is published, e.g. to compress
+// or encrypt it. Subscribers reverse the transform with WithPayloadTransform.
+func WithPayloadEncode(encode func([]byte) ([]byte, error)) PublishOption {
+	return func(o *publishOptions) {
+		o.encode = encode
+	}
+}
+
+// WithMsgID sets the Nats-Msg-Id header used by JetStream for deduplication. Publishing the
+// same ID again within the stream's duplicate window stores the message only once, so retried
+// publishes are idempotent; the acknowledgement of the retry reports Duplicate.
+func WithMsgID(id string) PublishOption {
+	return func(o *publishOptions) {
+		o.jsOpts = append(o.jsOpts, jetstream.WithMsgID(id))
+	}
+}
+
+// WithPublishCodec encodes the message with codec instead of the client's event codec.
+// The codec's content type is sent in the HeaderContentType header either way.
+func WithPublishCodec(codec Codec) PublishOption {
+	return func(o *publishOptions) {
+		o.codec = codec
+	}
+}
+
+// WithExpectLastSubjectSeq publishes only if the last message stored on the subject has
+// sequence seq, zero meaning the subject holds no messages yet. It provides optimistic
+// concurrency for event-sourced aggregates: when another writer appended first the publish
+// fails with ErrWrongSequence and the caller can reload the aggregate and retry.
+func WithExpectLastSubjectSeq(seq uint64) PublishOption {
+	return func(o *publishOptions) {
+		o.jsOpts = append(o.jsOpts, jetstream.WithExpectLastSequencePerSubject(seq))
+	}
+}
+
+// WithSchemaVersion sends version, e.g. "2", in the HeaderSchemaVersion header so consumers
+// can tell which revision of the message schema the producer used.
+func WithSchemaVersion(version string) PublishOption {
+	return func(o *publishOptions) {
+		o.schemaVersion = version
+	}
+}
+
+// WithPublishDryRun validates the message without sending it: Publish encodes it, runs the
+// validators set with WithValidators, checks the payload size and, when connected, that a
+// stream captures the subject. The returned acknowledgement names that stream and has a zero
+// sequence.
+func WithPublishDryRun() PublishOption {
+	return func(o *publishOptions) {
+		o.dryRun = true
+	}
+}
@@ -0,0 +1,130 @@
+package rimnats
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+// testCipher encrypts payloads with AES-GCM, prefixing them with their nonce.
+type testCipher struct {
+	aead cipher.AEAD
+}
+
+func newTestCipher(t *testing.T) *testCipher {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("gcm: %v", err)
+	}
+
+	return &testCipher{aead: aead}
+}
+
+func (c *testCipher) encrypt(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func (c *testCipher) decrypt(data []byte) ([]byte, error) {
+	if len(data) < c.aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+
+	return c.aead.Open(nil, nonce, sealed, nil)
+}
+
+func TestPayloadEncryptionRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	stream := createTestStream(t, client, "products", "product.>")
+	crypter := newTestCipher(t)
+
+	received := make(chan *v1.ProductCreated, 1)
+	_, err := client.Subscribe(ctx, "product.created", "products", "payload_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			received <- msg.(*v1.ProductCreated)
+			return m.Ack()
+		}, WithPayloadTransform(crypter.decrypt))
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	sent := &v1.ProductCreated{Id: "1", Name: "confidential chair"}
+	if _, err := client.Publish(ctx, "product.created", sent, WithPayloadEncode(crypter.encrypt)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if !proto.Equal(got, sent) {
+			t.Errorf("received %v, want %v", got, sent)
+		}
+	case <-ctx.Done():
+		t.Fatal("message not received")
+	}
+
+	raw, err := stream.GetLastMsgForSubject(ctx, "product.created")
+	if err != nil {
+		t.Fatalf("get raw message: %v", err)
+	}
+	plain, _ := proto.Marshal(sent)
+	if bytes.Equal(raw.Data, plain) || bytes.Contains(raw.Data, []byte("confidential chair")) {
+		t.Error("payload stored in plain text")
+	}
+}
+
+func TestPublishWithMsgIDDeduplicates(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	stream := createTestStream(t, client, "products", "product.>")
+
+	ack, err := client.Publish(context.Background(), "product.created", &v1.ProductCreated{Id: "p-1"}, WithMsgID("product-p-1"))
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if ack.Duplicate {
+		t.Error("first publish was reported as a duplicate")
+	}
+
+	ack, err = client.Publish(context.Background(), "product.created", &v1.ProductCreated{Id: "p-1"}, WithMsgID("product-p-1"))
+	if err != nil {
+		t.Fatalf("publish again: %v", err)
+	}
+	if !ack.Duplicate {
+		t.Error("second publish with the same message ID was not reported as a duplicate")
+	}
+
+	info, err := stream.Info(context.Background())
+	if err != nil {
+		t.Fatalf("stream info: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("stream holds %d messages, want 1", info.State.Msgs)
+	}
+}
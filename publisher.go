@@ -2,47 +2,369 @@ package rimnats
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
 // Publish publishes a protobuf message to the specified NATS subject.
-// It marshals the protobuf message into bytes and publishes it using JetStream.
+// It encodes the message with the configured event codec and publishes it using JetStream.
 //
 // Parameters:
-//   - ctx: Context for the operation (currently unused)
+//   - ctx: Context bounding the publish and the wait for the acknowledgement
 //   - subject: The NATS subject to publish the message to
 //   - msg: The protobuf message to be published
-//   - opts: Optional publishing options for NATS
+//   - opts: Optional publishing options such as WithPublishOpts or WithPayloadEncode
 //
 // Returns:
+//   - *jetstream.PubAck: Acknowledgement with the stream sequence, nil when the message was buffered in the outbox
+//   - error: Returns an error if marshaling fails, if the payload exceeds the server's maximum
+//     payload size (ErrPayloadTooLarge) or if publishing fails
+func (n *rimNats) Publish(ctx context.Context, subject string, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	return n.PublishMsg(ctx, &Message{Subject: subject, Proto: msg}, opts...)
+}
+
+// PublishToStream publishes a protobuf message like Publish, but only if subject is bound to
+// stream. When no stream or a different stream captures the subject, JetStream rejects the
+// publish instead of the message being lost or stored elsewhere, which surfaces subject and
+// stream misconfiguration at publish time.
+//
+// Parameters:
+//   - ctx: Context bounding the publish and the wait for the acknowledgement
+//   - stream: The stream expected to store the message
+//   - subject: The NATS subject to publish the message to
+//   - msg: The protobuf message to be published
+//   - opts: Optional publishing options such as WithMsgID or WithPayloadEncode
+//
+// Returns:
+//   - *jetstream.PubAck: Acknowledgement with the stream sequence, nil when the message was buffered in the outbox
+//   - error: Returns an error if no stream or another stream is bound to subject, or if publishing fails
+func (n *rimNats) PublishToStream(ctx context.Context, stream, subject string, msg proto.Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	opts = append(opts, WithPublishOpts(jetstream.WithExpectStream(stream)))
+
+	ack, err := n.PublishMsg(ctx, &Message{Subject: subject, Proto: msg}, opts...)
+	if err != nil {
+		n.loggR.Error("❌ [ rimnats ]: failed to publish to stream", "stream", stream, "subject", subject, "error", err)
+		return nil, err
+	}
+
+	return ack, nil
+}
+
+// PublishMsg publishes a protobuf message together with NATS headers, e.g. correlation IDs
+// or a Nats-Msg-Id idempotency key. It behaves like Publish otherwise.
+//
+// Parameters:
+//   - ctx: Context bounding the publish and the wait for the acknowledgement
+//   - msg: The message to publish, with its subject, payload and headers
+//   - opts: Optional publishing options such as WithPublishOpts or WithPayloadEncode
+//
+// Returns:
+//   - *jetstream.PubAck: Acknowledgement with the stream sequence, nil when the message was buffered in the outbox
 //   - error: Returns an error if marshaling fails or if publishing fails
-func (n *rimNats) Publish(ctx context.Context, subject string, msg proto.Message, opts ...jetstream.PublishOpt) error {
-	data, err := proto.Marshal(msg)
+func (n *rimNats) PublishMsg(ctx context.Context, msg *Message, opts ...PublishOption) (*jetstream.PubAck, error) {
+	ctx, span := n.startSpan(ctx, "publish", msg.Subject, trace.SpanKindProducer)
+	defer span.End()
+
+	ack, err := n.publish(ctx, msg, newPublishOptions(opts))
+	if err != nil {
+		recordSpanError(span, err)
+	}
+
+	return ack, err
+}
+
+// publish encodes msg and publishes it with JetStream, buffering it in the outbox while disconnected.
+func (n *rimNats) publish(ctx context.Context, msg *Message, options *publishOptions) (*jetstream.PubAck, error) {
+	subject := msg.Subject
+	natsMsg, err := n.encodeMsg(msg, options)
+	if err != nil {
+		return nil, err
+	}
+	n.propagator().Inject(ctx, headerCarrier(natsMsg.Header))
+
+	if err := n.checkPayloadSize(natsMsg); err != nil {
+		return nil, wrapError("publish", "", subject, err)
+	}
+
+	if n.cfg.DryRun || options.dryRun {
+		return n.dryRun(ctx, subject)
+	}
+
+	if err := n.waitRateLimit(ctx, subject); err != nil {
+		return nil, wrapError("publish", "", subject, err)
+	}
+
+	if n.cfg.PublishWait > 0 && !n.waitForConnection(ctx, n.cfg.PublishWait) && n.outbox == nil {
+		return nil, wrapError("publish", "", subject, ErrDisconnected)
+	}
+
+	if n.outbox != nil && (n.conn == nil || !n.conn.IsConnected()) {
+		if err := n.outbox.push(natsMsg, options.jsOpts); err != nil {
+			return nil, wrapError("publish", "", subject, err)
+		}
+
+		if n.cfg.Debug {
+			n.loggR.Info("📦 [ rimnats ]: buffered message until reconnected", "subject", subject)
+		}
+
+		return nil, nil
+	}
+
+	start := time.Now()
+	ack, err := retry(ctx, options.retry, func() (*jetstream.PubAck, error) {
+		return n.publisher().PublishMsg(ctx, natsMsg, options.jsOpts...)
+	}, func(attempt int, err error) {
+		if n.cfg.Debug {
+			n.loggR.Info("🔁 [ rimnats ]: retrying publish", "subject", subject, "attempt", attempt, "error", err)
+		}
+	})
+	if err != nil {
+		n.cfg.Metrics.observePublished(subject, "", time.Since(start), err)
+		if n.cfg.Debug {
+			n.loggR.Info("❌ [ rimnats ]: failed to publish message", "subject", subject, "error", err)
+		}
+
+		return nil, wrapError("publish", "", subject, err)
+	}
+
+	n.cfg.Metrics.observePublished(subject, ack.Stream, time.Since(start), nil)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(attrStream, ack.Stream))
+
+	if n.cfg.Debug {
+		n.loggR.Info("🚀 [ rimnats ]: published message",
+			"subject", subject,
+			"stream", ack.Stream,
+			"sequence", ack.Sequence,
+			"domain", ack.Domain,
+			"duplicate", ack.Duplicate,
+		)
+	}
+
+	if options.capacity != nil {
+		n.checkCapacity(ctx, options.capacity, ack.Stream, subject)
+	}
+
+	return ack, nil
+}
+
+// flushConn flushes conn within ctx. FlushWithContext requires a deadline, so contexts
+// without one fall back to the default flush timeout.
+func flushConn(ctx context.Context, conn *nats.Conn) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return conn.Flush()
+	}
+
+	return conn.FlushWithContext(ctx)
+}
+
+// dryRun completes a dry-run publish of a message on subject that was encoded and validated,
+// reporting the stream capturing subject when connected instead of publishing it.
+func (n *rimNats) dryRun(ctx context.Context, subject string) (*jetstream.PubAck, error) {
+	ack := &jetstream.PubAck{}
+	if n.js != nil && n.conn.IsConnected() {
+		stream, err := n.js.StreamNameBySubject(ctx, subject)
+		if err != nil {
+			return nil, wrapError("publish", "", subject, err)
+		}
+		ack.Stream = stream
+	}
+
+	n.loggR.Info("🧪 [ rimnats ]: dry run, message not published", "subject", subject, "stream", ack.Stream)
+
+	return ack, nil
+}
+
+// PublishAsync publishes a protobuf message without waiting for the JetStream acknowledgement,
+// so producers can pipeline many messages before blocking. The returned future resolves once
+// the server acknowledges the message; use PublishAsyncComplete to wait for every pending ack.
+// Messages published asynchronously are never buffered in the outbox.
+//
+// Parameters:
+//   - ctx: Context checked before publishing
+//   - subject: The NATS subject to publish the message to
+//   - msg: The protobuf message to be published
+//   - opts: Optional publishing options such as WithPublishOpts or WithPayloadEncode
+//
+// Returns:
+//   - jetstream.PubAckFuture: Future resolving to the acknowledgement or the publish error
+//   - error: Returns an error if encoding fails or too many publishes are pending
+func (n *rimNats) PublishAsync(ctx context.Context, subject string, msg proto.Message, opts ...PublishOption) (jetstream.PubAckFuture, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapError("publish async", "", subject, err)
+	}
+
+	options := newPublishOptions(opts)
+
+	natsMsg, err := n.encodeMsg(&Message{Subject: subject, Proto: msg}, options)
+	if err != nil {
+		return nil, err
+	}
+	n.propagator().Inject(ctx, headerCarrier(natsMsg.Header))
+
+	if err := n.checkPayloadSize(natsMsg); err != nil {
+		return nil, wrapError("publish async", "", subject, err)
+	}
+
+	if err := n.waitRateLimit(ctx, subject); err != nil {
+		return nil, wrapError("publish async", "", subject, err)
+	}
+
+	future, err := n.publisher().PublishMsgAsync(natsMsg, options.jsOpts...)
 	if err != nil {
 		if n.cfg.Debug {
-			n.loggR.Info("❌ [ rimnats ]: failed to encode protobuf: %v", err)
+			n.loggR.Info("❌ [ rimnats ]: failed to publish message asynchronously", "subject", subject, "error", err)
+		}
+
+		return nil, wrapError("publish async", "", subject, err)
+	}
+
+	if options.capacity != nil {
+		n.checkCapacity(ctx, options.capacity, "", subject)
+	}
+
+	return future, nil
+}
+
+// PublishAsyncComplete returns a channel that is closed once every message published with
+// PublishAsync has been acknowledged or failed.
+func (n *rimNats) PublishAsyncComplete() <-chan struct{} {
+	if n.pool != nil {
+		return n.pool.publishAsyncComplete()
+	}
+
+	return n.js.PublishAsyncComplete()
+}
+
+// Flush blocks until everything published so far has reached the server: it flushes the
+// connection's write buffer and waits for the acknowledgement of every PublishAsync call.
+// Call it before exiting or reporting success upstream. Messages still buffered in the
+// outbox while disconnected are not waited for.
+func (n *rimNats) Flush(ctx context.Context) error {
+	if err := flushConn(ctx, n.conn); err != nil {
+		return wrapError("flush", "", "", err)
+	}
+
+	if n.pool != nil {
+		if err := n.pool.flush(ctx); err != nil {
+			return wrapError("flush", "", "", err)
+		}
+	}
+
+	select {
+	case <-n.PublishAsyncComplete():
+	case <-ctx.Done():
+		return wrapError("flush", "", "", ctx.Err())
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🚿 [ rimnats ]: flushed pending publishes")
+	}
+
+	return nil
+}
+
+// encodeMsg marshals msg with the event codec or the codec of options, applies the payload transform of options and
+// returns the NATS message carrying it along with a copy of the message headers.
+func (n *rimNats) encodeMsg(msg *Message, options *publishOptions) (*nats.Msg, error) {
+	codec := n.cfg.EventCodec
+	if options.codec != nil {
+		codec = options.codec
+	}
+
+	for _, validate := range n.cfg.Validators {
+		if err := validate(msg.Subject, msg.Proto); err != nil {
+			if n.cfg.Debug {
+				n.loggR.Info("❌ [ rimnats ]: message failed validation", "subject", msg.Subject, "error", err)
+			}
+
+			return nil, err
 		}
+	}
 
-		return err
+	data, err := codec.Marshal(msg.Proto)
+	if err == nil && options.encode != nil {
+		data, err = options.encode(data)
 	}
 
-	ack, err := n.js.Publish(ctx, subject, data, opts...)
 	if err != nil {
 		if n.cfg.Debug {
-			n.loggR.Info("❌ [ rimnats ]: failed to publish message: %v", err)
+			n.loggR.Info("❌ [ rimnats ]: failed to encode message", "subject", msg.Subject, "error", err)
 		}
 
-		return err
+		return nil, err
+	}
+
+	natsMsg := nats.NewMsg(msg.Subject)
+	natsMsg.Data = data
+	for key, values := range msg.Headers {
+		natsMsg.Header[key] = append([]string(nil), values...)
+	}
+	natsMsg.Header.Set(HeaderContentType, codec.ContentType())
+	if msg.Proto != nil {
+		natsMsg.Header.Set(HeaderMessageType, messageType(msg.Proto))
+	}
+	if options.schemaVersion != "" {
+		natsMsg.Header.Set(HeaderSchemaVersion, options.schemaVersion)
+	}
+
+	return natsMsg, nil
+}
+
+// PublishBatch publishes msgs to subject asynchronously and waits for all acknowledgements
+// together, so a batch costs roughly one round-trip instead of one per message. Failed
+// messages are reported together in the returned error, annotated with their index in msgs.
+// When ctx is done the batch stops publishing and waiting and returns ctx's error.
+//
+// Parameters:
+//   - ctx: Context bounding the publishes and the wait for the acknowledgements
+//   - subject: The NATS subject to publish the messages to
+//   - msgs: The protobuf messages to be published
+//   - opts: Optional publishing options applied to every message
+//
+// Returns:
+//   - error: Returns the joined errors of the messages that failed, or nil when all were acknowledged
+func (n *rimNats) PublishBatch(ctx context.Context, subject string, msgs []proto.Message, opts ...PublishOption) error {
+	var errs []error
+	futures := make(map[int]jetstream.PubAckFuture, len(msgs))
+	for i, msg := range msgs {
+		future, err := n.PublishAsync(ctx, subject, msg, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return wrapError("publish batch", "", subject, ctx.Err())
+			}
+
+			errs = append(errs, fmt.Errorf("message %d: %w", i, err))
+			continue
+		}
+
+		futures[i] = future
+	}
+
+	for i := range msgs {
+		future, ok := futures[i]
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			errs = append(errs, fmt.Errorf("message %d: %w", i, wrapError("publish batch", "", subject, err)))
+		case <-ctx.Done():
+			return wrapError("publish batch", "", subject, ctx.Err())
+		}
 	}
 
 	if n.cfg.Debug {
-		n.loggR.Info("🚀 [ rimnats ]: published message on domain: %s", ack.Domain)
-		n.loggR.Info("🚀 [ rimnats ]: published message on sequence: %d", ack.Sequence)
-		n.loggR.Info("🚀 [ rimnats ]: published message on duplicate: %v", ack.Duplicate)
-		n.loggR.Info("🚀 [ rimnats ]: published message on stream: %s", ack.Stream)
+		n.loggR.Info("🚀 [ rimnats ]: published batch", "subject", subject, "count", len(msgs), "failed", len(errs))
 	}
 
-	return err
+	return errors.Join(errs...)
 }
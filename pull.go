@@ -0,0 +1,177 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// WithPullExpiry sets how long a single pull request waits for messages before expiring.
+// Subscribe requires at least one second; Pull accepts any positive duration.
+func WithPullExpiry(d time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.pullExpiry = d
+		o.consumeOpts = append(o.consumeOpts, jetstream.PullExpiry(d))
+	}
+}
+
+// WithPullNoWait makes Pull return as soon as the messages already available are delivered,
+// instead of waiting up to the pull expiry for more to arrive. Pulls on an empty stream return
+// immediately. It has no effect on Subscribe, which keeps pulling continuously.
+func WithPullNoWait(noWait bool) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.pullNoWait = noWait
+	}
+}
+
+// Pull performs a single pull of up to batch messages from the durable consumer and processes
+// them with handler, returning the number of messages delivered. Unlike Subscribe it does not
+// keep consuming, so polling workers control when the next batch is pulled. The pull waits up to
+// the pull expiry (the ctx deadline or 30 seconds by default) for messages unless WithPullNoWait is set.
+//
+// Parameters:
+//   - subject: The NATS subject to pull from
+//   - stream: The stream name for the consumer
+//   - durable: The durable name for the consumer; must not be empty
+//   - batch: Maximum number of messages to pull
+//   - factory: A function that creates new instances of the protobuf message type
+//   - handler: A function that processes decoded protobuf messages
+//   - opts: Optional subscription options such as WithPullExpiry or WithPullNoWait
+//
+// Returns:
+//   - int: Number of messages delivered to the handler
+//   - error: Returns an error if the consumer cannot be created or the pull fails
+func (n *rimNats) Pull(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	batch int,
+	factory func() proto.Message,
+	handler ProtoHandler,
+	opts ...SubscribeOption,
+) (int, error) {
+	options := newSubscribeOptions(opts)
+
+	msgs, err := n.pull(ctx, "pull", subject, stream, durable, batch, options)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for m := range msgs.Messages() {
+		n.handle(ctx, m, factory, handler, options)
+		handled++
+	}
+
+	if err := msgs.Error(); err != nil && !errors.Is(err, jetstream.ErrNoMessages) {
+		return handled, wrapError("pull", stream, subject, err)
+	}
+
+	return handled, nil
+}
+
+// pull issues a single pull request honoring the pull expiry and no-wait options. It rejects
+// an empty durable without WithConsumerName, which would start a new ephemeral consumer
+// delivering the stream from the beginning on every call.
+func (n *rimNats) pull(
+	ctx context.Context,
+	op string,
+	subject string,
+	stream string,
+	durable string,
+	batch int,
+	options *subscribeOptions,
+) (jetstream.MessageBatch, error) {
+	if durable == "" && options.consumerName == "" {
+		err := fmt.Errorf("%w: %s needs a durable or consumer name", ErrInvalidConsumerConfig, op)
+		return nil, wrapError(op, stream, subject, err)
+	}
+
+	_, consumer, err := n.createConsumer(ctx, op, []string{subject}, stream, durable, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs jetstream.MessageBatch
+	if options.pullNoWait {
+		msgs, err = consumer.FetchNoWait(batch)
+	} else {
+		expiry := options.pullExpiry
+		if deadline, ok := ctx.Deadline(); expiry <= 0 && ok {
+			expiry = time.Until(deadline)
+		}
+
+		if expiry <= 0 {
+			expiry = 30 * time.Second
+		}
+
+		msgs, err = consumer.Fetch(batch, jetstream.FetchMaxWait(expiry))
+	}
+
+	if err != nil {
+		return nil, wrapError(op, stream, subject, err)
+	}
+
+	return msgs, nil
+}
+
+// DecodedMsg is a message returned by Fetch, decoded into its protobuf type.
+type DecodedMsg struct {
+	Proto proto.Message // Decoded payload
+	Msg   jetstream.Msg // Underlying JetStream message, used to acknowledge it
+}
+
+// Fetch pulls up to batch messages from the durable consumer and returns them decoded, leaving
+// acknowledgment to the caller. Batch workers use it to process a batch before pulling the next,
+// controlling back-pressure themselves. Messages that fail to decode are NAKed and left out.
+//
+// Parameters:
+//   - subject: The NATS subject to fetch from
+//   - stream: The stream name for the consumer
+//   - durable: The durable name for the consumer; must not be empty
+//   - batch: Maximum number of messages to fetch
+//   - factory: A function that creates new instances of the protobuf message type
+//   - opts: Optional subscription options such as WithPullExpiry or WithPullNoWait
+//
+// Returns:
+//   - []DecodedMsg: The decoded messages, empty when none arrived before the pull expired
+//   - error: Returns an error if the consumer cannot be created or the fetch fails
+func (n *rimNats) Fetch(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	batch int,
+	factory func() proto.Message,
+	opts ...SubscribeOption,
+) ([]DecodedMsg, error) {
+	options := newSubscribeOptions(opts)
+
+	msgs, err := n.pull(ctx, "fetch", subject, stream, durable, batch, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded []DecodedMsg
+	collect := func(_ context.Context, msg proto.Message, m jetstream.Msg) error {
+		decoded = append(decoded, DecodedMsg{Proto: msg, Msg: m})
+		return nil
+	}
+
+	for m := range msgs.Messages() {
+		if err := n.process(ctx, m, factory, collect, options); err != nil {
+			n.fail(ctx, m, err, options)
+		}
+	}
+
+	if err := msgs.Error(); err != nil && !errors.Is(err, jetstream.ErrNoMessages) {
+		return decoded, wrapError("fetch", stream, subject, err)
+	}
+
+	return decoded, nil
+}
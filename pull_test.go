@@ -0,0 +1,46 @@
+package rimnats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPullNoWaitOnEmptyStream(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "products", "product.>")
+
+	factory := func() proto.Message { return &v1.ProductCreated{} }
+	handler := func(ctx context.Context, msg proto.Message, m jetstream.Msg) error { return m.Ack() }
+
+	start := time.Now()
+	handled, err := client.Pull(context.Background(), "product.created", "products", "pull_test", 10, factory, handler,
+		WithPullExpiry(5*time.Second), WithPullNoWait(true))
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if handled != 0 {
+		t.Errorf("handled %d messages, want 0", handled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("no-wait pull took %v, want it to return before the 5s expiry", elapsed)
+	}
+
+	// Messages already available are still delivered
+	if _, err := client.Publish(context.Background(), "product.created", &v1.ProductCreated{}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	handled, err = client.Pull(context.Background(), "product.created", "products", "pull_test", 10, factory, handler,
+		WithPullExpiry(5*time.Second), WithPullNoWait(true))
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("handled %d messages, want 1", handled)
+	}
+}
@@ -0,0 +1,34 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// publishLimit throttles the publishes on subjects matching subject with a token bucket.
+type publishLimit struct {
+	subject string        // Subject or wildcard pattern the limit applies to
+	limiter *rate.Limiter // Token bucket shared by every matching subject
+}
+
+// waitRateLimit blocks until every rate limit matching subject allows another publish. It
+// returns an ErrRateLimited error when ctx is done, or its deadline would pass, first.
+func (n *rimNats) waitRateLimit(ctx context.Context, subject string) error {
+	for _, limit := range n.cfg.RateLimits {
+		if !subjectMatches(limit.subject, subject) {
+			continue
+		}
+
+		if err := limit.limiter.Wait(ctx); err != nil {
+			if n.cfg.Debug {
+				n.loggR.Info("🚦 [ rimnats ]: publish rate limit exceeded", "subject", subject, "limit", limit.subject, "error", err)
+			}
+
+			return fmt.Errorf("%w: %w", ErrRateLimited, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+)
+
+func TestPublishRateLimit(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t),
+		WithPublishRateLimit("limited.>", 2),
+		WithPublishRateLimit("unlimited.>", 0),
+		WithPublishRateLimit("negative.>", -1),
+	)
+	createTestStream(t, client, "limited", "limited.>")
+	createTestStream(t, client, "unlimited", "unlimited.>", "negative.>")
+
+	if len(client.cfg.RateLimits) != 1 {
+		t.Fatalf("%d rate limits, want only the positive one", len(client.cfg.RateLimits))
+	}
+
+	for _, subject := range []string{"unlimited.created", "negative.created"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		for i := range 20 {
+			if _, err := client.Publish(ctx, subject, &v1.ProductCreated{}); err != nil {
+				t.Fatalf("publish %d to %s: %v", i, subject, err)
+			}
+		}
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The burst of two passes, the third publish would wait past the deadline
+	for i := range 2 {
+		if _, err := client.Publish(ctx, "limited.created", &v1.ProductCreated{}); err != nil {
+			t.Fatalf("publish %d: %v", i, err)
+		}
+	}
+	if _, err := client.Publish(ctx, "limited.created", &v1.ProductCreated{}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("publish past the limit: got %v, want ErrRateLimited", err)
+	}
+}
@@ -0,0 +1,113 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// FactoryRegistry maps subjects to the protobuf factory used to decode messages on them,
+// so the factory is registered once instead of being passed to every call. Subjects may
+// contain the NATS wildcards "*" and ">"; an exact registration wins over a wildcard one,
+// and among wildcard registrations the most specific pattern wins (see Factory).
+type FactoryRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]func() proto.Message
+	patterns  []string // Wildcard subjects in registration order
+}
+
+// NewFactoryRegistry creates an empty factory registry.
+func NewFactoryRegistry() *FactoryRegistry {
+	return &FactoryRegistry{factories: make(map[string]func() proto.Message)}
+}
+
+// RegisterFactory registers factory for messages on subject, replacing any previous registration.
+func (r *FactoryRegistry) RegisterFactory(subject string, factory func() proto.Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[subject]; !ok && strings.ContainsAny(subject, "*>") {
+		r.patterns = append(r.patterns, subject)
+	}
+	r.factories[subject] = factory
+}
+
+// Factory returns the factory registered for subject, falling back to a wildcard
+// registration matching it. When several patterns match, the most specific one wins:
+// patterns are compared token by token and the first differing token decides, a literal
+// beating "*" and "*" beating ">". Patterns equally specific fall back to the first
+// registered. The bool is false when no factory applies.
+func (r *FactoryRegistry) Factory(subject string) (func() proto.Message, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if factory, ok := r.factories[subject]; ok {
+		return factory, true
+	}
+
+	best := ""
+	for _, pattern := range r.patterns {
+		if subjectMatches(pattern, subject) && (best == "" || moreSpecific(pattern, best)) {
+			best = pattern
+		}
+	}
+
+	if best == "" {
+		return nil, false
+	}
+
+	return r.factories[best], true
+}
+
+// moreSpecific reports whether pattern a is strictly more specific than pattern b.
+func moreSpecific(a, b string) bool {
+	aTokens := strings.Split(a, ".")
+	bTokens := strings.Split(b, ".")
+
+	for i := 0; i < len(aTokens) && i < len(bTokens); i++ {
+		if rankA, rankB := tokenRank(aTokens[i]), tokenRank(bTokens[i]); rankA != rankB {
+			return rankA > rankB
+		}
+	}
+
+	// A longer pattern matching the same subject pins down more tokens
+	return len(aTokens) > len(bTokens)
+}
+
+// tokenRank orders subject tokens by specificity: literals, then "*", then ">".
+func tokenRank(token string) int {
+	switch token {
+	case ">":
+		return 0
+	case "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// RegisterFactory registers factory for messages on subject in the client's factory registry.
+func (n *rimNats) RegisterFactory(subject string, factory func() proto.Message) {
+	n.factories.RegisterFactory(subject, factory)
+}
+
+// SubscribeRegistered works like Subscribe but decodes messages with the factory
+// registered for subject, returning ErrNoFactory when none has been registered.
+func (n *rimNats) SubscribeRegistered(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	handler ProtoHandler,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	factory, ok := n.factories.Factory(subject)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoFactory, subject)
+	}
+
+	return n.Subscribe(ctx, subject, stream, durable, factory, handler, opts...)
+}
@@ -0,0 +1,77 @@
+package rimnats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestSubscribeRegistered(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "products", "product.>")
+	client.RegisterFactory("product.created", func() proto.Message { return &v1.ProductCreated{} })
+
+	received := make(chan proto.Message, 1)
+	_, err := client.SubscribeRegistered(ctx, "product.created", "products", "registry_test",
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			received <- msg
+			return m.Ack()
+		})
+	if err != nil {
+		t.Fatalf("subscribe registered: %v", err)
+	}
+
+	if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: "1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if got, ok := msg.(*v1.ProductCreated); !ok || got.GetId() != "1" {
+			t.Errorf("received %v, want ProductCreated 1", msg)
+		}
+	case <-ctx.Done():
+		t.Fatal("message not received")
+	}
+}
+
+func TestFactoryPrefersMostSpecificPattern(t *testing.T) {
+	registry := NewFactoryRegistry()
+	registry.RegisterFactory("product.>", func() proto.Message { return &structpb.Value{} })
+	registry.RegisterFactory("product.*.created", func() proto.Message { return &structpb.Struct{} })
+	registry.RegisterFactory("product.chair.*", func() proto.Message { return &v1.ProductCreated{} })
+	registry.RegisterFactory("product.table.created", func() proto.Message { return &v1.Event{} })
+
+	tests := map[string]proto.Message{
+		"product.chair.created": &v1.ProductCreated{}, // chair beats *
+		"product.lamp.created":  &structpb.Struct{},   // * beats >
+		"product.lamp.deleted":  &structpb.Value{},
+		"product.table.created": &v1.Event{}, // exact registrations win
+	}
+
+	for subject, want := range tests {
+		// The winner must not depend on map iteration order
+		for range 20 {
+			factory, ok := registry.Factory(subject)
+			if !ok {
+				t.Fatalf("no factory for %s", subject)
+			}
+			if got := factory(); got.ProtoReflect().Descriptor() != want.ProtoReflect().Descriptor() {
+				t.Fatalf("factory for %s creates %s, want %s", subject, got.ProtoReflect().Descriptor().FullName(), want.ProtoReflect().Descriptor().FullName())
+			}
+		}
+	}
+
+	if _, ok := registry.Factory("order.created"); ok {
+		t.Error("factory found for an unregistered subject")
+	}
+}
@@ -0,0 +1,95 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// replayBatch is the number of messages Replay fetches at once.
+const replayBatch = 100
+
+// replayWait is how long Replay waits for more messages before treating the stream as caught up.
+const replayWait = time.Second
+
+// Replay re-processes the messages stored on subject in stream from sequence startSeq onwards,
+// e.g. after fixing a consumer bug, without touching the state of any durable consumer. It
+// reads them with a temporary ordered consumer and returns once it has caught up with the
+// stream. Messages are decoded with the factory registered for subject; Replay returns
+// ErrNoFactory when there is none. A failing handler stops the replay, and the returned error
+// names the sequence to resume from.
+//
+// Parameters:
+//   - ctx: Context bounding the replay
+//   - stream: The stream holding the messages
+//   - subject: The subject to replay, may contain wildcards
+//   - startSeq: The stream sequence of the first message to replay
+//   - handler: A function that processes decoded protobuf messages, acknowledging them has no effect
+//   - opts: Optional subscription options such as WithPayloadTransform or WithSubscribeCodec
+//
+// Returns:
+//   - error: Returns an error if the consumer cannot be created, fetching fails or the handler fails
+func (n *rimNats) Replay(ctx context.Context, stream, subject string, startSeq uint64, handler ProtoHandler, opts ...SubscribeOption) error {
+	factory, ok := n.factories.Factory(subject)
+	if !ok {
+		return wrapError("replay", stream, subject, fmt.Errorf("%w: %s", ErrNoFactory, subject))
+	}
+
+	options := newSubscribeOptions(opts)
+
+	consumer, err := n.js.OrderedConsumer(ctx, stream, jetstream.OrderedConsumerConfig{
+		FilterSubjects: []string{subject},
+		DeliverPolicy:  jetstream.DeliverByStartSequencePolicy,
+		OptStartSeq:    startSeq,
+	})
+	if err != nil {
+		return wrapError("replay", stream, subject, err)
+	}
+
+	handler = n.middleware.wrap(handler)
+	replayed := 0
+	for {
+		batch, err := consumer.Fetch(replayBatch, jetstream.FetchMaxWait(replayWait))
+		if err != nil {
+			return wrapError("replay", stream, subject, err)
+		}
+
+		received := 0
+		caughtUp := false
+		for m := range batch.Messages() {
+			received++
+
+			meta, err := m.Metadata()
+			if err != nil {
+				return wrapError("replay", stream, subject, err)
+			}
+
+			if err := n.process(ctx, m, factory, handler, options); err != nil {
+				return wrapError("replay", stream, subject, fmt.Errorf("message %d: %w", meta.Sequence.Stream, err))
+			}
+
+			replayed++
+			caughtUp = meta.NumPending == 0
+		}
+
+		if err := batch.Error(); err != nil {
+			return wrapError("replay", stream, subject, err)
+		}
+
+		if received == 0 || caughtUp {
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			return wrapError("replay", stream, subject, err)
+		}
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("⏪ [ rimnats ]: replayed messages", "stream", stream, "subject", subject, "start", startSeq, "count", replayed)
+	}
+
+	return nil
+}
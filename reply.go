@@ -2,49 +2,206 @@ package rimnats
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
+// ReplyOption configures a responder registered with Reply and its variants.
+type ReplyOption func(*replyOptions)
+
+// replyOptions holds the per-responder settings applied by ReplyOption.
+type replyOptions struct {
+	dedup    DedupStore    // Store caching responses by request ID, nil disables deduplication
+	dedupTTL time.Duration // Time a cached response is reused for retries
+}
+
+// StreamingHandler handles a request that may take a long time to answer.
+// It can call heartbeat periodically to tell the requester it is still working.
+type StreamingHandler func(ctx context.Context, req proto.Message, heartbeat func() error) (proto.Message, error)
+
 // Reply sets up a handler that receives protobuf request messages and responds with protobuf replies.
+// The handler context expires when the requester stops waiting for the response.
 // - subject: Subject to listen for requests on
 // - reqFactory: Function that returns a new instance of the request message type
 // - handler: Function to handle the request and return a response
-func (n *rimNats) Reply(subject string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error)) error {
-	_, err := n.conn.Subscribe(subject, func(m *nats.Msg) {
+// - opts: Optional responder options such as WithRequestDedup
+func (n *rimNats) Reply(subject string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...ReplyOption) error {
+	return n.reply(subject, "", reqFactory, func(ctx context.Context, req proto.Message, _ func() error) (proto.Message, error) {
+		return handler(ctx, req)
+	}, opts...)
+}
+
+// ReplyQueue works like Reply but joins the queue group queue, so each request is answered by
+// exactly one member of a pool of service instances instead of by every replica.
+// - subject: Subject to listen for requests on
+// - queue: Queue group shared by the service instances
+// - reqFactory: Function that returns a new instance of the request message type
+// - handler: Function to handle the request and return a response
+// - opts: Optional responder options such as WithRequestDedup
+func (n *rimNats) ReplyQueue(subject, queue string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...ReplyOption) error {
+	return n.reply(subject, queue, reqFactory, func(ctx context.Context, req proto.Message, _ func() error) (proto.Message, error) {
+		return handler(ctx, req)
+	}, opts...)
+}
+
+// ReplyStreaming sets up a handler for long-running requests. The handler receives a heartbeat
+// function that sends a heartbeat message to the requester, which resets its timeout when the
+// request was sent with WithHeartbeat. Heartbeats are skipped for requesters that do not accept them.
+// - subject: Subject to listen for requests on
+// - reqFactory: Function that returns a new instance of the request message type
+// - handler: Function to handle the request, send heartbeats and return a response
+// - opts: Optional responder options such as WithRequestDedup
+func (n *rimNats) ReplyStreaming(subject string, reqFactory func() proto.Message, handler StreamingHandler, opts ...ReplyOption) error {
+	return n.reply(subject, "", reqFactory, handler, opts...)
+}
+
+// reply subscribes handler to requests on subject, joining queue when it is not empty.
+func (n *rimNats) reply(subject, queue string, reqFactory func() proto.Message, handler StreamingHandler, opts ...ReplyOption) error {
+	var options replyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	msgHandler := func(m *nats.Msg) {
+		n.inflight.Add(1)
+		defer n.inflight.Done()
+
+		// Answer in the encoding the requester used
+		codec := codecFor(n.cfg.RPCCodec, m.Header.Get(HeaderContentType))
+
+		ctx, cancel := requestContext(m.Header)
+		defer cancel()
+
+		// Reserve the request ID so a duplicate waits for this request's response
+		var answer []byte
+		requestID := m.Header.Get(HeaderRequestID)
+		dedupID := dedupKey(m.Subject, requestID)
+		if options.dedup != nil && requestID != "" {
+			call, cached := n.reserveRequest(ctx, options.dedup, dedupID)
+			if cached != nil {
+				if n.cfg.Debug {
+					n.loggR.Info("♻️ [ rimnats ]: answered duplicate request from cache", "subject", m.Subject, "request_id", requestID)
+				}
+				_ = respond(m, codec, cached)
+				return
+			}
+			if call == nil {
+				return
+			}
+
+			defer func() { n.requests.finish(dedupID, call, answer) }()
+		}
+
 		req := reqFactory()
-		if err := proto.Unmarshal(m.Data, req); err != nil {
+		if err := codec.Unmarshal(m.Data, req); err != nil {
 			if n.cfg.Debug {
-				n.loggR.Error("❌ [ rimnats ]: failed to unmarshal request: %v", err)
+				n.loggR.Error("❌ [ rimnats ]: failed to unmarshal request", "subject", m.Subject, "error", err)
 			}
+			_ = respondError(m, &ServiceError{Code: "400", Description: err.Error()})
 			return
 		}
 
-		resp, err := handler(context.Background(), req)
+		heartbeat := func() error {
+			if m.Header.Get(HeaderHeartbeat) == "" {
+				return nil
+			}
+
+			beat := nats.NewMsg(m.Reply)
+			beat.Header.Set(HeaderHeartbeat, "1")
+			return n.conn.PublishMsg(beat)
+		}
+
+		ctx = n.propagator().Extract(ctx, headerCarrier(m.Header))
+		ctx, span := n.startSpan(ctx, "reply", m.Subject, trace.SpanKindServer)
+		defer span.End()
+
+		resp, err := n.callReplyHandler(ctx, m.Subject, handler, req, heartbeat)
 		if err != nil {
+			recordSpanError(span, err)
 			if n.cfg.Debug {
-				n.loggR.Error("❌ [ rimnats ]: request handler failed: %v", err)
+				n.loggR.Error("❌ [ rimnats ]: request handler failed", "subject", m.Subject, "error", err)
 			}
-			// Optionally send an error message (could serialize error into protobuf)
-			_ = m.Respond([]byte{})
+			_ = respondError(m, err)
 			return
 		}
 
-		data, err := proto.Marshal(resp)
+		data, err := codec.Marshal(resp)
 		if err != nil {
+			recordSpanError(span, err)
 			if n.cfg.Debug {
-				n.loggR.Error("❌ [ rimnats ]: failed to marshal response: %v", err)
+				n.loggR.Error("❌ [ rimnats ]: failed to marshal response", "subject", m.Subject, "error", err)
 			}
+			_ = respondError(m, err)
 			return
 		}
 
-		_ = m.Respond(data)
-	})
+		if options.dedup != nil && requestID != "" {
+			options.dedup.Set(dedupID, data, options.dedupTTL)
+			answer = data
+		}
 
-	if err != nil && n.cfg.Debug {
-		n.loggR.Error("❌ [ rimnats ]: failed to subscribe for reply on %s: %v", subject, err)
+		_ = respond(m, codec, data)
 	}
 
-	return err
+	sub, err := n.conn.QueueSubscribe(subject, queue, msgHandler)
+	if err != nil {
+		if n.cfg.Debug {
+			n.loggR.Error("❌ [ rimnats ]: failed to subscribe for reply", "subject", subject, "error", err)
+		}
+
+		return err
+	}
+
+	subscription := &Subscription{subject: subject, sub: sub}
+	subscription.restore = func(context.Context) error {
+		return n.restoreSub(subscription, queue, msgHandler)
+	}
+	n.subs.add(subscription)
+
+	return nil
+}
+
+// callReplyHandler calls handler, turning a panic into an error so the requester receives
+// an error response and the subscription keeps running.
+func (n *rimNats) callReplyHandler(ctx context.Context, subject string, handler StreamingHandler, req proto.Message, heartbeat func() error) (resp proto.Message, err error) {
+	defer recoverPanic(n.loggR, subject, &err)
+
+	return handler(ctx, req, heartbeat)
+}
+
+// respond answers m with data encoded by codec, labelling it with the codec's content type.
+func respond(m *nats.Msg, codec Codec, data []byte) error {
+	resp := nats.NewMsg(m.Reply)
+	resp.Data = data
+	resp.Header.Set(HeaderContentType, codec.ContentType())
+
+	return m.RespondMsg(resp)
+}
+
+// requestContext returns the context for handling a request with header, carrying the deadline
+// from its HeaderTimeout header. Requests without the header use context.Background.
+func requestContext(header nats.Header) (context.Context, context.CancelFunc) {
+	timeout, err := time.ParseDuration(header.Get(HeaderTimeout))
+	if err != nil || timeout <= 0 {
+		return context.Background(), func() {}
+	}
+
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// respondError answers m with an empty response carrying the service error headers, so the
+// requester can tell a failed handler apart from a valid empty response.
+func respondError(m *nats.Msg, err error) error {
+	serviceErr := &ServiceError{Code: "500", Description: err.Error()}
+	errors.As(err, &serviceErr)
+
+	resp := nats.NewMsg(m.Reply)
+	resp.Header.Set(HeaderServiceError, serviceErr.Description)
+	resp.Header.Set(HeaderServiceErrorCode, serviceErr.Code)
+
+	return m.RespondMsg(resp)
 }
@@ -0,0 +1,52 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReplyStreamingHeartbeats(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	// The work takes well over the request timeout but heartbeats every 50ms
+	err := client.ReplyStreaming("report.build", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message, heartbeat func() error) (proto.Message, error) {
+			for range 10 {
+				time.Sleep(50 * time.Millisecond)
+				if err := heartbeat(); err != nil {
+					return nil, err
+				}
+			}
+			return &v1.SayHelloResponse{Message: "done"}, nil
+		})
+	if err != nil {
+		t.Fatalf("reply streaming: %v", err)
+	}
+
+	var beats atomic.Int32
+	resp, err := client.Request(context.Background(), "report.build", &v1.SayHelloRequest{}, func() proto.Message { return &v1.SayHelloResponse{} },
+		200*time.Millisecond, WithHeartbeat(func() { beats.Add(1) }))
+	if err != nil {
+		t.Fatalf("request with heartbeats: %v", err)
+	}
+	if got := resp.(*v1.SayHelloResponse).GetMessage(); got != "done" {
+		t.Errorf("response = %q, want done", got)
+	}
+	if beats.Load() == 0 {
+		t.Error("no heartbeats received")
+	}
+
+	// Without accepting heartbeats the same request times out
+	_, err = client.Request(context.Background(), "report.build", &v1.SayHelloRequest{}, func() proto.Message { return &v1.SayHelloResponse{} },
+		200*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("request without heartbeats: got %v, want ErrTimeout", err)
+	}
+}
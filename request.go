@@ -4,38 +4,192 @@ import (
 	"context"
 	"time"
 
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
+// RequestOption configures a single Request call.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the per-call settings applied by RequestOption.
+type requestOptions struct {
+	heartbeat func() // Invoked for every heartbeat received from a streaming responder
+	requestID string // ID sent in the HeaderRequestID header, empty when not set
+}
+
+// WithHeartbeat accepts heartbeats from a responder registered with ReplyStreaming.
+// Every heartbeat invokes fn and restarts the request timeout, so long-running work does not
+// time out while the responder is still alive. fn may be nil.
+func WithHeartbeat(fn func()) RequestOption {
+	return func(o *requestOptions) {
+		if fn == nil {
+			fn = func() {}
+		}
+
+		o.heartbeat = fn
+	}
+}
+
 // Request sends a protobuf message as a request and waits for a protobuf reply.
 // - subject: The NATS subject to send the request to
 // - req: The protobuf message to send
 // - factory: A function that returns a new instance of the expected reply message
-// - timeout: How long to wait for a response
-func (n *rimNats) Request(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration) (proto.Message, error) {
-	data, err := proto.Marshal(req)
+// - timeout: How long to wait for a response, zero relies on the deadline of ctx
+// - opts: Optional per-request options such as WithHeartbeat
+//
+// The time left until the request gives up is sent to the responder in the HeaderTimeout
+// header, so Reply handlers can stop working once the requester is no longer waiting.
+//
+// When the responder's handler fails, Request returns a *ServiceError describing the failure.
+// Otherwise failures match ErrTimeout when no response arrived in time, ErrNoResponders when
+// nobody listens on subject and ErrUnmarshal when the response cannot be decoded.
+func (n *rimNats) Request(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, opts ...RequestOption) (proto.Message, error) {
+	resp, err := n.RequestFull(ctx, subject, req, factory, timeout, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	return resp.Message, nil
+}
+
+// Response is a reply received by RequestFull together with its metadata.
+type Response struct {
+	Message proto.Message // Decoded reply, nil when the responder reported an error
+	Header  nats.Header   // Headers sent by the responder
+	Subject string        // Subject the request was sent to
+	Reply   string        // Inbox the response was delivered on
+	Error   *ServiceError // Failure reported by the responder, nil when the handler succeeded
+}
+
+// IsError reports whether the responder answered with an error envelope.
+func (r *Response) IsError() bool {
+	return r.Error != nil
+}
+
+// RequestFull works like Request but returns the reply together with its headers and subjects.
+// A failure reported by the responder is not returned as an error; it is set on Response.Error
+// instead, so callers can inspect the error envelope and its headers.
+func (n *rimNats) RequestFull(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, opts ...RequestOption) (*Response, error) {
+	var options requestOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx, span := n.startSpan(ctx, "request", subject, trace.SpanKindClient)
+	defer span.End()
+
+	resp, err := n.request(ctx, subject, req, factory, timeout, &options)
+	switch {
+	case err != nil:
+		recordSpanError(span, err)
+	case resp.Error != nil:
+		recordSpanError(span, resp.Error)
+	}
+
+	return resp, err
+}
+
+// request sends req and waits for the reply, restarting the timeout on heartbeats when enabled.
+func (n *rimNats) request(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, timeout time.Duration, options *requestOptions) (*Response, error) {
+	data, err := n.cfg.RPCCodec.Marshal(req)
 	if err != nil {
 		if n.cfg.Debug {
-			n.loggR.Error("❌ [ rimnats ]: failed to marshal request: %v", err)
+			n.loggR.Error("❌ [ rimnats ]: failed to marshal request", "subject", subject, "error", err)
 		}
 		return nil, err
 	}
 
-	msg, err := n.conn.RequestWithContext(ctx, subject, data)
+	// With heartbeats the timeout restarts on every heartbeat, so only ctx bounds the request
+	if options.heartbeat == nil && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	msg.Header.Set(HeaderContentType, n.cfg.RPCCodec.ContentType())
+	n.propagator().Inject(ctx, headerCarrier(msg.Header))
+	if deadline, ok := ctx.Deadline(); ok {
+		msg.Header.Set(HeaderTimeout, time.Until(deadline).String())
+	}
+
+	if options.requestID != "" {
+		msg.Header.Set(HeaderRequestID, options.requestID)
+	}
+
+	if options.heartbeat != nil {
+		msg, err = n.requestWithHeartbeat(ctx, msg, timeout, options.heartbeat)
+	} else {
+		msg, err = n.conn.RequestMsgWithContext(ctx, msg)
+	}
+
 	if err != nil {
 		if n.cfg.Debug {
-			n.loggR.Error("❌ [ rimnats ]: request error: %v", err)
+			n.loggR.Error("❌ [ rimnats ]: request error", "subject", subject, "error", err)
 		}
-		return nil, err
+		return nil, wrapError("request", "", subject, err)
+	}
+
+	resp := &Response{Header: msg.Header, Subject: subject, Reply: msg.Subject}
+	if serviceErr := serviceError(msg.Header); serviceErr != nil {
+		resp.Error = serviceErr
+		return resp, nil
 	}
 
 	reply := factory()
-	if err := proto.Unmarshal(msg.Data, reply); err != nil {
+	if err := codecFor(n.cfg.RPCCodec, msg.Header.Get(HeaderContentType)).Unmarshal(msg.Data, reply); err != nil {
 		if n.cfg.Debug {
-			n.loggR.Error("❌ [ rimnats ]: failed to unmarshal response: %v", err)
+			n.loggR.Error("❌ [ rimnats ]: failed to unmarshal response", "subject", subject, "error", err)
 		}
+		return nil, wrapError("request", "", subject, unmarshalError(err))
+	}
+
+	resp.Message = reply
+	return resp, nil
+}
+
+// requestWithHeartbeat sends a request on a dedicated inbox and waits for the final response,
+// restarting the timeout whenever the responder sends a heartbeat.
+func (n *rimNats) requestWithHeartbeat(ctx context.Context, msg *nats.Msg, timeout time.Duration, onHeartbeat func()) (*nats.Msg, error) {
+	inbox := n.conn.NewRespInbox()
+	sub, err := n.conn.SubscribeSync(inbox)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	msg.Reply = inbox
+	msg.Header.Set(HeaderHeartbeat, "1")
+	if err := n.conn.PublishMsg(msg); err != nil {
 		return nil, err
 	}
 
-	return reply, nil
+	for {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		reply, err := sub.NextMsgWithContext(waitCtx)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		if reply.Header.Get("Status") == "503" {
+			return nil, nats.ErrNoResponders
+		}
+
+		if reply.Header.Get(HeaderHeartbeat) == "" {
+			return reply, nil
+		}
+
+		onHeartbeat()
+	}
 }
@@ -0,0 +1,111 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestMany sends req once and gathers the replies of every responder on subject
+// (scatter-gather), e.g. for service discovery or quorum queries. It returns once maxResponses
+// replies have arrived or window has elapsed, whichever comes first; maxResponses of zero
+// gathers replies for the whole window. Replies carrying a service error are skipped.
+//
+// Parameters:
+//   - ctx: Context bounding the request, cancelling it returns the replies gathered so far
+//   - subject: The NATS subject to send the request to
+//   - req: The protobuf message to send
+//   - factory: A function that returns a new instance of the expected reply message
+//   - maxResponses: Number of replies to wait for, zero for no limit
+//   - window: How long to gather replies
+//
+// Returns:
+//   - []proto.Message: The decoded replies in the order they arrived
+//   - error: Returns an error if the request cannot be sent, no responders are available,
+//     or ctx is done before any reply arrived
+func (n *rimNats) RequestMany(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, maxResponses int, window time.Duration) ([]proto.Message, error) {
+	ctx, span := n.startSpan(ctx, "request", subject, trace.SpanKindClient)
+	defer span.End()
+
+	replies, err := n.requestMany(ctx, subject, req, factory, maxResponses, window)
+	if err != nil {
+		recordSpanError(span, err)
+	}
+
+	return replies, err
+}
+
+// requestMany publishes req with a dedicated inbox and collects the replies delivered on it.
+func (n *rimNats) requestMany(ctx context.Context, subject string, req proto.Message, factory func() proto.Message, maxResponses int, window time.Duration) ([]proto.Message, error) {
+	data, err := n.cfg.RPCCodec.Marshal(req)
+	if err != nil {
+		if n.cfg.Debug {
+			n.loggR.Error("❌ [ rimnats ]: failed to marshal request", "subject", subject, "error", err)
+		}
+		return nil, err
+	}
+
+	windowCtx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	inbox := n.conn.NewInbox()
+	sub, err := n.conn.SubscribeSync(inbox)
+	if err != nil {
+		return nil, wrapError("request many", "", subject, err)
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	msg := nats.NewMsg(subject)
+	msg.Reply = inbox
+	msg.Data = data
+	msg.Header.Set(HeaderContentType, n.cfg.RPCCodec.ContentType())
+	msg.Header.Set(HeaderTimeout, window.String())
+	n.propagator().Inject(ctx, headerCarrier(msg.Header))
+
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return nil, wrapError("request many", "", subject, err)
+	}
+
+	var replies []proto.Message
+	for maxResponses <= 0 || len(replies) < maxResponses {
+		m, err := sub.NextMsgWithContext(windowCtx)
+		if err != nil {
+			// The window closing ends the gathering normally
+			windowClosed := ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded)
+			if windowClosed || len(replies) > 0 {
+				break
+			}
+			return nil, wrapError("request many", "", subject, err)
+		}
+
+		if m.Header.Get("Status") == "503" {
+			if len(replies) == 0 {
+				return nil, wrapError("request many", "", subject, nats.ErrNoResponders)
+			}
+			continue
+		}
+
+		if serviceErr := serviceError(m.Header); serviceErr != nil {
+			if n.cfg.Debug {
+				n.loggR.Info("❌ [ rimnats ]: responder reported an error", "subject", subject, "code", serviceErr.Code, "error", serviceErr.Description)
+			}
+			continue
+		}
+
+		reply := factory()
+		if err := codecFor(n.cfg.RPCCodec, m.Header.Get(HeaderContentType)).Unmarshal(m.Data, reply); err != nil {
+			if n.cfg.Debug {
+				n.loggR.Error("❌ [ rimnats ]: failed to unmarshal response", "subject", subject, "error", err)
+			}
+			continue
+		}
+
+		replies = append(replies, reply)
+	}
+
+	return replies, nil
+}
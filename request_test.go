@@ -0,0 +1,94 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequestFull(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	err := client.Reply("greeter.hello", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			name := req.(*v1.SayHelloRequest).GetName()
+			if name == "" {
+				return nil, &ServiceError{Code: "400", Description: "name is required"}
+			}
+			return &v1.SayHelloResponse{Message: "hello " + name}, nil
+		})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	factory := func() proto.Message { return &v1.SayHelloResponse{} }
+
+	resp, err := client.RequestFull(context.Background(), "greeter.hello", &v1.SayHelloRequest{Name: "ada"}, factory, time.Second)
+	if err != nil {
+		t.Fatalf("request full: %v", err)
+	}
+	if resp.IsError() {
+		t.Fatalf("successful response reported error %v", resp.Error)
+	}
+	if got := resp.Message.(*v1.SayHelloResponse).GetMessage(); got != "hello ada" {
+		t.Errorf("message = %q, want hello ada", got)
+	}
+	if resp.Subject != "greeter.hello" || resp.Reply == "" {
+		t.Errorf("subject = %q, reply = %q", resp.Subject, resp.Reply)
+	}
+	if got := resp.Header.Get(HeaderContentType); got != "application/protobuf" {
+		t.Errorf("content type header = %q, want application/protobuf", got)
+	}
+
+	resp, err = client.RequestFull(context.Background(), "greeter.hello", &v1.SayHelloRequest{}, factory, time.Second)
+	if err != nil {
+		t.Fatalf("request full with failing handler: %v", err)
+	}
+	if !resp.IsError() {
+		t.Fatal("error envelope not reported")
+	}
+	if resp.Error.Code != "400" || resp.Error.Description != "name is required" {
+		t.Errorf("error = %+v, want 400 name is required", resp.Error)
+	}
+	if resp.Message != nil {
+		t.Errorf("message = %v, want nil for an error envelope", resp.Message)
+	}
+
+	// Request reports the same failure as an error
+	_, err = client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{}, factory, time.Second)
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.Code != "400" {
+		t.Errorf("request: got %v, want a 400 ServiceError", err)
+	}
+}
+
+func TestRequestDetectsErrorWithoutDescription(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	// A responder that reports only an error code
+	_, err := client.conn.Subscribe("greeter.hello", func(m *nats.Msg) {
+		resp := nats.NewMsg(m.Reply)
+		resp.Header.Set(HeaderServiceErrorCode, "503")
+		_ = m.RespondMsg(resp)
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	resp, err := client.RequestFull(context.Background(), "greeter.hello", &v1.SayHelloRequest{Name: "ada"}, func() proto.Message { return &v1.SayHelloResponse{} }, time.Second)
+	if err != nil {
+		t.Fatalf("request full: %v", err)
+	}
+	if !resp.IsError() || resp.Error.Code != "503" {
+		t.Errorf("error = %+v, want code 503", resp.Error)
+	}
+	if resp.Message != nil {
+		t.Errorf("message = %v, want nil for an error envelope", resp.Message)
+	}
+}
@@ -0,0 +1,108 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// restoreTimeout bounds the time spent restoring the subscriptions after a reconnect.
+const restoreTimeout = 30 * time.Second
+
+// restoreSubscriptions re-establishes the subscriptions that did not survive a reconnect:
+// JetStream consumers the server no longer knows, e.g. ephemeral consumers lost when the
+// server restarted, and core NATS subscriptions such as Reply handlers that became invalid.
+func (n *rimNats) restoreSubscriptions() {
+	ctx, cancel := context.WithTimeout(context.Background(), restoreTimeout)
+	defer cancel()
+
+	for _, s := range n.subs.list() {
+		if s.restore == nil {
+			continue
+		}
+
+		if err := s.restore(ctx); err != nil {
+			n.loggR.Error("❌ [ rimnats ]: failed to restore subscription", "subject", s.subject, "stream", s.stream, "error", err)
+		}
+	}
+}
+
+// restoreConsumer recreates the consumer of s and consumes it again when the server no
+// longer has it. Instead of its original deliver policy the recreated consumer starts at the
+// first message not yet handled, the lowest one still being handled when handlers run
+// concurrently, so handled messages are not delivered again and none is skipped. The unacked
+// age monitor is restarted for it.
+// Consumers that still exist keep being consumed by the running consume context.
+func (n *rimNats) restoreConsumer(
+	ctx context.Context,
+	s *Subscription,
+	jetStream jetstream.Stream,
+	subjects []string,
+	durable string,
+	consume jetstream.MessageHandler,
+	options *subscribeOptions,
+) error {
+	_, err := jetStream.Consumer(ctx, s.Consumer())
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, jetstream.ErrConsumerNotFound) {
+		return wrapError("resubscribe", s.stream, s.subject, err)
+	}
+
+	restored := options
+	if seq := s.resumeSequence(); seq > 0 {
+		resume := *options
+		resume.deliverPolicy = jetstream.DeliverByStartSequencePolicy
+		resume.startSeq = seq
+		resume.startTime = nil
+		restored = &resume
+	}
+
+	_, consumer, err := n.createConsumer(ctx, "resubscribe", subjects, s.stream, durable, restored)
+	if err != nil {
+		return err
+	}
+
+	consumeCtx, err := consumer.Consume(consume, options.consumeOpts...)
+	if err != nil {
+		return wrapError("resubscribe", s.stream, s.subject, err)
+	}
+
+	name := consumer.CachedInfo().Name
+	s.replaceConsumer(name, consumeCtx)
+
+	// The monitor of the previous consumer stopped with its consume context
+	if options.unackedAge != nil {
+		go n.monitorUnackedAge(options.unackedAge, jetStream, consumer, consumeCtx, name, consumer.CachedInfo().Config.FilterSubject)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🔄 [ rimnats ]: recreated consumer after reconnect", "subject", s.subject, "stream", s.stream, "consumer", name)
+	}
+
+	return nil
+}
+
+// restoreSub subscribes handler again when the core NATS subscription of s is no longer valid.
+func (n *rimNats) restoreSub(s *Subscription, queue string, handler nats.MsgHandler) error {
+	if sub := s.coreSub(); sub != nil && sub.IsValid() {
+		return nil
+	}
+
+	sub, err := n.conn.QueueSubscribe(s.subject, queue, handler)
+	if err != nil {
+		return wrapError("resubscribe", "", s.subject, err)
+	}
+
+	s.replaceSub(sub)
+
+	if n.cfg.Debug {
+		n.loggR.Info("🔄 [ rimnats ]: resubscribed after reconnect", "subject", s.subject)
+	}
+
+	return nil
+}
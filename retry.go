@@ -0,0 +1,73 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// RetryPolicy describes how a failed publish is retried. Only transient failures such as
+// no responders during a JetStream leader election or a timeout waiting for the
+// acknowledgement are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts including the first one
+	BaseDelay   time.Duration // Delay before the first retry
+	Factor      float64       // Multiplier applied to the delay after every retry, values below 1 keep it constant
+	Jitter      float64       // Fraction of the delay randomly added or removed, between 0 and 1
+}
+
+// DefaultRetryPolicy retries a publish up to 5 times starting at 50ms and doubling the delay.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 5, BaseDelay: 50 * time.Millisecond, Factor: 2, Jitter: 0.2}
+
+// WithPublishRetry retries publishes that fail with a transient error according to policy,
+// stopping early when ctx is done. Combine it with WithMsgID so a retry of a publish whose
+// acknowledgement was lost is not stored twice.
+func WithPublishRetry(policy RetryPolicy) PublishOption {
+	return func(o *publishOptions) {
+		o.retry = &policy
+	}
+}
+
+// delay returns the time to wait before retry number attempt, starting at 1.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.BaseDelay)
+	for i := 1; i < attempt && p.Factor > 1; i++ {
+		d *= p.Factor
+	}
+
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(d)
+}
+
+// retry calls fn until it succeeds, fails with a permanent error, policy runs out of attempts
+// or ctx is done. A nil policy calls fn once.
+func retry[T any](ctx context.Context, policy *RetryPolicy, fn func() (T, error), onRetry func(attempt int, err error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || !isRetryablePublishError(err) {
+			return result, err
+		}
+
+		onRetry(attempt, err)
+
+		select {
+		case <-time.After(policy.delay(attempt)):
+		case <-ctx.Done():
+			return result, err
+		}
+	}
+}
+
+// isRetryablePublishError reports whether a publish failure is likely transient.
+func isRetryablePublishError(err error) bool {
+	return errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, nats.ErrTimeout) ||
+		errors.Is(err, jetstream.ErrNoStreamResponse)
+}
@@ -0,0 +1,42 @@
+// Package rimnatstest provides helpers for testing code built on rimnats.
+package rimnatstest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// StartServer starts an embedded NATS server with JetStream enabled for the duration of t
+// and returns its URL. The server listens on a random free port and stores JetStream data in
+// a temporary directory, so parallel tests do not collide. It is shut down on t.Cleanup.
+func StartServer(t testing.TB) string {
+	t.Helper()
+
+	opts := &server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	}
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("rimnatstest: failed to create NATS server: %v", err)
+	}
+
+	go srv.Start()
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+
+	if !srv.ReadyForConnections(10 * time.Second) {
+		t.Fatalf("rimnatstest: NATS server not ready for connections")
+	}
+
+	return srv.ClientURL()
+}
@@ -0,0 +1,112 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// Router dispatches the messages of a single consumer to handlers registered by subject, so
+// a consumer on a wildcard subject such as "sample.>" can decode and handle "sample.created"
+// and "sample.updated" differently. Patterns may contain the NATS wildcards "*" and ">".
+type Router struct {
+	mu     sync.RWMutex
+	routes []route
+}
+
+// route is a handler registered on a Router.
+type route struct {
+	pattern string               // Subject pattern the route matches
+	factory func() proto.Message // Factory creating the message type of the route
+	handler ProtoHandler         // Handler processing the decoded messages
+}
+
+// factoryResolver returns the factory decoding messages on subject.
+type factoryResolver func(subject string) (func() proto.Message, error)
+
+// NewRouter creates a router without routes.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle registers handler for messages whose subject matches pattern, decoding them into
+// messages created by factory. An exact pattern wins over wildcard ones; otherwise the first
+// matching pattern registered is used.
+func (r *Router) Handle(pattern string, factory func() proto.Message, handler ProtoHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes = append(r.routes, route{pattern: pattern, factory: factory, handler: handler})
+}
+
+// match returns the route for subject. The bool is false when no route matches.
+func (r *Router) match(subject string) (route, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rt := range r.routes {
+		if rt.pattern == subject {
+			return rt, true
+		}
+	}
+
+	for _, rt := range r.routes {
+		if subjectMatches(rt.pattern, subject) {
+			return rt, true
+		}
+	}
+
+	return route{}, false
+}
+
+// factory returns the factory of the route matching subject.
+func (r *Router) factory(subject string) (func() proto.Message, error) {
+	rt, ok := r.match(subject)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoHandler, subject)
+	}
+
+	return rt.factory, nil
+}
+
+// dispatch passes a decoded message to the handler of the route matching its subject.
+func (r *Router) dispatch(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+	rt, ok := r.match(m.Subject())
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoHandler, m.Subject())
+	}
+
+	return rt.handler(ctx, msg, m)
+}
+
+// SubscribeRouter subscribes a single consumer to subject and dispatches each message to the
+// route of router matching its concrete subject. Messages without a matching route fail
+// with ErrNoHandler and are terminated, so they are not redelivered.
+//
+// Parameters:
+//   - subject: The NATS subject to subscribe to, usually containing wildcards
+//   - stream: The stream name for the subscription
+//   - durable: The durable name for the subscription
+//   - router: The routes messages are dispatched to
+//   - opts: Optional subscription options
+//
+// Returns:
+//   - *Subscription: Handle used to stop the subscription
+//   - error: Returns an error if the subscription setup fails
+func (n *rimNats) SubscribeRouter(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	router *Router,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	opts = append(opts, func(o *subscribeOptions) {
+		o.factoryFor = router.factory
+	})
+
+	return n.subscribe(ctx, []string{subject}, stream, durable, nil, router.dispatch, opts...)
+}
@@ -0,0 +1,33 @@
+package rimnats
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+// messageType returns the fully-qualified protobuf name of msg.
+func messageType(msg proto.Message) string {
+	return string(msg.ProtoReflect().Descriptor().FullName())
+}
+
+// checkMessageType reports an ErrMessageType error when the message type named in header
+// differs from the type of msg, the instance the payload is about to be decoded into.
+// Messages without the header, e.g. from older producers, are accepted.
+func checkMessageType(header nats.Header, msg proto.Message) error {
+	published := header.Get(HeaderMessageType)
+	if published == "" {
+		return nil
+	}
+
+	if expected := messageType(msg); published != expected {
+		return fmt.Errorf("%w: got %s, expected %s", ErrMessageType, published, expected)
+	}
+
+	return nil
+}
+
+// Validator checks a message before it is published on subject, e.g. that required fields
+// are set. Register validators with WithValidators.
+type Validator func(subject string, msg proto.Message) error
@@ -0,0 +1,130 @@
+package rimnats
+
+import (
+	"container/heap"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// SeenStore records which messages a subscription has already processed, so redelivered
+// messages can be skipped. Keys are the message's Nats-Msg-Id header, or its stream name
+// and sequence when the header is absent.
+type SeenStore interface {
+	// Seen reports whether key has already been marked as processed.
+	Seen(ctx context.Context, key string) (bool, error)
+	// MarkSeen records key as processed.
+	MarkSeen(ctx context.Context, key string) error
+}
+
+// MemorySeenStore is a SeenStore kept in process memory. It only deduplicates redeliveries
+// to the same process; use a KVSeenStore to share processed keys between replicas.
+type MemorySeenStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	keys     map[string]time.Time
+	expiries dedupExpiries
+	now      func() time.Time
+}
+
+// NewMemorySeenStore returns an in-memory SeenStore forgetting keys after ttl, zero to keep
+// them for the lifetime of the process.
+func NewMemorySeenStore(ttl time.Duration) *MemorySeenStore {
+	return &MemorySeenStore{ttl: ttl, keys: make(map[string]time.Time), now: time.Now}
+}
+
+// Seen reports whether key was marked within the store's ttl.
+func (s *MemorySeenStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	markedAt, ok := s.keys[key]
+	if ok && s.ttl > 0 && s.now().Sub(markedAt) > s.ttl {
+		delete(s.keys, key)
+		return false, nil
+	}
+
+	return ok, nil
+}
+
+// MarkSeen records key, evicting expired keys in expiry order along the way.
+func (s *MemorySeenStore) MarkSeen(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.keys[key] = now
+	if s.ttl <= 0 {
+		return nil
+	}
+
+	for len(s.expiries) > 0 && now.After(s.expiries[0].expiresAt) {
+		expired := heap.Pop(&s.expiries).(dedupExpiry)
+		// Skip expiries of keys that were evicted by Seen or marked again since
+		if markedAt, ok := s.keys[expired.id]; ok && markedAt.Add(s.ttl).Equal(expired.expiresAt) {
+			delete(s.keys, expired.id)
+		}
+	}
+	heap.Push(&s.expiries, dedupExpiry{id: key, expiresAt: now.Add(s.ttl)})
+
+	return nil
+}
+
+// KVSeenStore is a SeenStore backed by a JetStream key-value bucket, shared by every replica
+// of a service. Configure the bucket's TTL to bound how long processed keys are remembered.
+type KVSeenStore struct {
+	bucket jetstream.KeyValue
+}
+
+// NewKVSeenStore returns a SeenStore recording processed keys in bucket, e.g. one created
+// with CreateKVBucket and obtained from KVStore.Bucket.
+func NewKVSeenStore(bucket jetstream.KeyValue) *KVSeenStore {
+	return &KVSeenStore{bucket: bucket}
+}
+
+// Seen reports whether key exists in the bucket.
+func (s *KVSeenStore) Seen(ctx context.Context, key string) (bool, error) {
+	_, err := s.bucket.Get(ctx, kvSeenKey(key))
+	if errors.Is(err, jetstream.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, wrapError("seen", "", "", err)
+	}
+
+	return true, nil
+}
+
+// MarkSeen stores key in the bucket.
+func (s *KVSeenStore) MarkSeen(ctx context.Context, key string) error {
+	if _, err := s.bucket.Put(ctx, kvSeenKey(key), nil); err != nil {
+		return wrapError("mark seen", "", "", err)
+	}
+
+	return nil
+}
+
+// kvSeenKey encodes key into the characters allowed in key-value keys.
+func kvSeenKey(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// seenKey returns the idempotency key of m: its Nats-Msg-Id header, or its stream and stream
+// sequence when the header is absent. It is empty when neither is available.
+func seenKey(m jetstream.Msg) string {
+	if id := m.Headers().Get(jetstream.MsgIDHeader); id != "" {
+		return id
+	}
+
+	meta, err := m.Metadata()
+	if err != nil {
+		return ""
+	}
+
+	return meta.Stream + "." + strconv.FormatUint(meta.Sequence.Stream, 10)
+}
@@ -0,0 +1,39 @@
+package rimnats
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemorySeenStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemorySeenStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	_ = store.MarkSeen(ctx, "a")
+	_ = store.MarkSeen(ctx, "b")
+
+	now = now.Add(30 * time.Second)
+	_ = store.MarkSeen(ctx, "a")
+	_ = store.MarkSeen(ctx, "c")
+
+	// Marking another key evicts b, but not a which was marked again
+	now = now.Add(45 * time.Second)
+	_ = store.MarkSeen(ctx, "d")
+
+	if len(store.keys) != 3 {
+		t.Errorf("store holds %d keys, want a, c and d", len(store.keys))
+	}
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if seen, _ := store.Seen(ctx, key); seen != want {
+			t.Errorf("Seen(%s) = %v, want %v", key, seen, want)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	if seen, _ := store.Seen(ctx, "d"); seen {
+		t.Error("expired key reported as seen")
+	}
+}
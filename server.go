@@ -0,0 +1,81 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// ServerInfo reports the version of the server the client is connected to and whether
+// JetStream is enabled for the account. It returns ErrDisconnected when the client is not
+// connected.
+func (n *rimNats) ServerInfo() (version string, jetStreamEnabled bool, err error) {
+	if n.conn == nil || !n.conn.IsConnected() {
+		return "", false, wrapError("server info", "", "", ErrDisconnected)
+	}
+
+	version = n.conn.ConnectedServerVersion()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := n.js.AccountInfo(ctx); err != nil {
+		if errors.Is(err, jetstream.ErrJetStreamNotEnabled) || errors.Is(err, jetstream.ErrJetStreamNotEnabledForAccount) {
+			return version, false, nil
+		}
+
+		return version, false, wrapError("server info", "", "", err)
+	}
+
+	return version, true, nil
+}
+
+// SupportsPerMessageTTL reports whether the connected server supports per-message TTLs,
+// which were introduced in NATS Server 2.11.
+func (n *rimNats) SupportsPerMessageTTL() bool {
+	return n.serverAtLeast(2, 11, 0)
+}
+
+// SupportsSubjectTransforms reports whether the connected server supports subject
+// transforms on streams, which were introduced in NATS Server 2.10.
+func (n *rimNats) SupportsSubjectTransforms() bool {
+	return n.serverAtLeast(2, 10, 0)
+}
+
+// serverAtLeast reports whether the connected server version is at least major.minor.patch.
+// It returns false when the client is not connected.
+func (n *rimNats) serverAtLeast(major, minor, patch int) bool {
+	if n.conn == nil || !n.conn.IsConnected() {
+		return false
+	}
+
+	want := [3]int{major, minor, patch}
+	got := parseServerVersion(n.conn.ConnectedServerVersion())
+	for i := range want {
+		if got[i] != want[i] {
+			return got[i] > want[i]
+		}
+	}
+
+	return true
+}
+
+// parseServerVersion parses a version such as "2.11.3" or "v2.10.0-beta.1" into its
+// major, minor and patch numbers. Missing or malformed parts are reported as zero.
+func parseServerVersion(version string) [3]int {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var parts [3]int
+	for i, part := range strings.SplitN(version, ".", 3) {
+		parts[i], _ = strconv.Atoi(part)
+	}
+
+	return parts
+}
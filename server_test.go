@@ -0,0 +1,76 @@
+package rimnats
+
+import (
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+)
+
+// embeddedServerVersion returns the version of the nats-server module rimnatstest runs.
+func embeddedServerVersion(t *testing.T) string {
+	t.Helper()
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("build info unavailable")
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/nats-io/nats-server/v2" {
+			return strings.TrimPrefix(dep.Version, "v")
+		}
+	}
+
+	t.Skip("nats-server not found in build info")
+	return ""
+}
+
+func TestServerInfo(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	version, jetStreamEnabled, err := client.ServerInfo()
+	if err != nil {
+		t.Fatalf("server info: %v", err)
+	}
+	if want := embeddedServerVersion(t); version != want {
+		t.Errorf("version = %q, want %q", version, want)
+	}
+	if !jetStreamEnabled {
+		t.Error("jetstream reported as disabled")
+	}
+
+	// The embedded server is 2.11, which has both features
+	if !client.SupportsPerMessageTTL() {
+		t.Error("per-message TTL reported as unsupported")
+	}
+	if !client.SupportsSubjectTransforms() {
+		t.Error("subject transforms reported as unsupported")
+	}
+}
+
+func TestServerInfoDisconnected(t *testing.T) {
+	client := New("nats://127.0.0.1:4222", WithLogger(&testLogger{}))
+
+	if _, _, err := client.ServerInfo(); err == nil {
+		t.Error("server info succeeded without a connection")
+	}
+	if client.SupportsPerMessageTTL() {
+		t.Error("per-message TTL reported as supported without a connection")
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	tests := map[string][3]int{
+		"2.11.9":      {2, 11, 9},
+		"2.10.0-beta": {2, 10, 0},
+		"v2.9":        {2, 9, 0},
+		"":            {0, 0, 0},
+	}
+
+	for version, want := range tests {
+		if got := parseServerVersion(version); got != want {
+			t.Errorf("parseServerVersion(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/micro"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// Service is a discoverable RPC service built on the NATS micro framework. Besides its
+// protobuf endpoints it answers the $SRV.PING, $SRV.INFO and $SRV.STATS discovery requests,
+// so it can be listed and monitored with the nats CLI.
+type Service struct {
+	client  *rimNats
+	service micro.Service
+}
+
+// AddService registers a micro service described by config, e.g. micro.Config{Name: "orders",
+// Version: "1.0.0"}. Endpoints are added to the returned service with AddEndpoint.
+func (n *rimNats) AddService(config micro.Config) (*Service, error) {
+	service, err := micro.AddService(n.conn, config)
+	if err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to add service", "service", config.Name, "error", err)
+		return nil, wrapError("add service", "", "", err)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🚀 [ rimnats ]: added service", "service", config.Name, "version", config.Version)
+	}
+
+	return &Service{client: n, service: service}, nil
+}
+
+// AddEndpoint registers an endpoint answering protobuf requests like Reply does. Requests and
+// responses are encoded with the client's RPC codec, or the codec matching the request's
+// content type. The endpoint listens on its name unless micro.WithEndpointSubject is passed.
+// - name: Name of the endpoint reported by the discovery requests
+// - reqFactory: Function that returns a new instance of the request message type
+// - handler: Function to handle the request and return a response
+// - opts: Optional endpoint options such as micro.WithEndpointSubject
+func (s *Service) AddEndpoint(name string, reqFactory func() proto.Message, handler func(context.Context, proto.Message) (proto.Message, error), opts ...micro.EndpointOpt) error {
+	n := s.client
+	err := s.service.AddEndpoint(name, micro.HandlerFunc(func(r micro.Request) {
+		n.inflight.Add(1)
+		defer n.inflight.Done()
+
+		codec := codecFor(n.cfg.RPCCodec, r.Headers().Get(HeaderContentType))
+		headers := micro.WithHeaders(micro.Headers{HeaderContentType: {codec.ContentType()}})
+
+		req := reqFactory()
+		if err := codec.Unmarshal(r.Data(), req); err != nil {
+			if n.cfg.Debug {
+				n.loggR.Error("❌ [ rimnats ]: failed to unmarshal request", "subject", r.Subject(), "error", err)
+			}
+			_ = r.Error("400", err.Error(), nil)
+			return
+		}
+
+		ctx, cancel := requestContext(nats.Header(r.Headers()))
+		defer cancel()
+
+		ctx = n.propagator().Extract(ctx, headerCarrier(r.Headers()))
+		ctx, span := n.startSpan(ctx, "reply", r.Subject(), trace.SpanKindServer)
+		defer span.End()
+
+		resp, err := n.callReplyHandler(ctx, r.Subject(), func(ctx context.Context, req proto.Message, _ func() error) (proto.Message, error) {
+			return handler(ctx, req)
+		}, req, nil)
+		if err == nil {
+			var data []byte
+			if data, err = codec.Marshal(resp); err == nil {
+				_ = r.Respond(data, headers)
+				return
+			}
+		}
+
+		recordSpanError(span, err)
+		if n.cfg.Debug {
+			n.loggR.Error("❌ [ rimnats ]: request handler failed", "subject", r.Subject(), "error", err)
+		}
+
+		serviceErr := &ServiceError{Code: "500", Description: err.Error()}
+		errors.As(err, &serviceErr)
+		_ = r.Error(serviceErr.Code, serviceErr.Description, nil)
+	}), opts...)
+
+	if err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to add endpoint", "service", s.service.Info().Name, "endpoint", name, "error", err)
+		return wrapError("add endpoint", "", name, err)
+	}
+
+	return nil
+}
+
+// Info returns the service description answered to $SRV.INFO requests.
+func (s *Service) Info() micro.Info {
+	return s.service.Info()
+}
+
+// Stats returns the request statistics of every endpoint answered to $SRV.STATS requests.
+func (s *Service) Stats() micro.Stats {
+	return s.service.Stats()
+}
+
+// Stop stops the service and its endpoints, draining requests already received.
+func (s *Service) Stop() error {
+	return s.service.Stop()
+}
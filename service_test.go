@@ -0,0 +1,61 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/micro"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServiceEndpoint(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+
+	service, err := client.AddService(micro.Config{Name: "greeter", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("add service: %v", err)
+	}
+	t.Cleanup(func() { _ = service.Stop() })
+
+	err = service.AddEndpoint("hello", func() proto.Message { return &v1.SayHelloRequest{} },
+		func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			name := req.(*v1.SayHelloRequest).GetName()
+			if name == "" {
+				return nil, &ServiceError{Code: "400", Description: "name is required"}
+			}
+			return &v1.SayHelloResponse{Message: "hello " + name}, nil
+		}, micro.WithEndpointSubject("greeter.hello"))
+	if err != nil {
+		t.Fatalf("add endpoint: %v", err)
+	}
+
+	factory := func() proto.Message { return &v1.SayHelloResponse{} }
+
+	resp, err := client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{Name: "ada"}, factory, time.Second)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if got := resp.(*v1.SayHelloResponse).GetMessage(); got != "hello ada" {
+		t.Errorf("message = %q, want hello ada", got)
+	}
+
+	_, err = client.Request(context.Background(), "greeter.hello", &v1.SayHelloRequest{}, factory, time.Second)
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.Code != "400" || serviceErr.Description != "name is required" {
+		t.Errorf("request: got %v, want a 400 ServiceError", err)
+	}
+
+	info := service.Info()
+	if len(info.Endpoints) != 1 || info.Endpoints[0].Subject != "greeter.hello" {
+		t.Errorf("endpoints = %+v, want hello on greeter.hello", info.Endpoints)
+	}
+
+	stats := service.Stats()
+	if len(stats.Endpoints) != 1 || stats.Endpoints[0].NumRequests != 2 || stats.Endpoints[0].NumErrors != 1 {
+		t.Errorf("endpoint stats = %+v, want 2 requests with 1 error", stats.Endpoints)
+	}
+}
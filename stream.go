@@ -0,0 +1,259 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// PurgeStream removes messages from the stream name while keeping the stream and its consumers.
+// Without options every message is removed; jetstream.WithPurgeSubject, WithPurgeSequence and
+// WithPurgeKeep narrow the purge.
+func (n *rimNats) PurgeStream(ctx context.Context, name string, opts ...jetstream.StreamPurgeOpt) error {
+	stream, err := n.js.Stream(ctx, name)
+	if err != nil {
+		return wrapError("purge stream", name, "", err)
+	}
+
+	if err := stream.Purge(ctx, opts...); err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to purge stream", "stream", name, "error", err)
+		return wrapError("purge stream", name, "", err)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🧹 [ rimnats ]: purged stream", "stream", name)
+	}
+
+	return nil
+}
+
+// DeleteStream deletes the stream name together with its messages and consumers.
+func (n *rimNats) DeleteStream(ctx context.Context, name string) error {
+	if err := n.js.DeleteStream(ctx, name); err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to delete stream", "stream", name, "error", err)
+		return wrapError("delete stream", name, "", err)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🗑️ [ rimnats ]: deleted stream", "stream", name)
+	}
+
+	return nil
+}
+
+// AddStreamSubject adds subject to the subjects captured by the stream name, leaving the rest
+// of its configuration untouched. Adding a subject the stream already captures does nothing.
+func (n *rimNats) AddStreamSubject(ctx context.Context, name, subject string) error {
+	return n.updateStreamSubjects(ctx, "add stream subject", name, subject, func(subjects []string) ([]string, error) {
+		if slices.Contains(subjects, subject) {
+			return nil, nil
+		}
+
+		return append(subjects, subject), nil
+	})
+}
+
+// RemoveStreamSubject removes subject from the subjects captured by the stream name, leaving
+// the rest of its configuration and the messages already stored untouched. Removing a subject
+// the stream does not capture does nothing; removing its last subject fails with
+// ErrInvalidStreamConfig.
+func (n *rimNats) RemoveStreamSubject(ctx context.Context, name, subject string) error {
+	return n.updateStreamSubjects(ctx, "remove stream subject", name, subject, func(subjects []string) ([]string, error) {
+		if !slices.Contains(subjects, subject) {
+			return nil, nil
+		}
+
+		if len(subjects) == 1 {
+			return nil, fmt.Errorf("%w: cannot remove the last subject %q", ErrInvalidStreamConfig, subject)
+		}
+
+		return slices.DeleteFunc(slices.Clone(subjects), func(s string) bool { return s == subject }), nil
+	})
+}
+
+// updateStreamSubjects fetches the current configuration of the stream name and updates it
+// with the subjects returned by mutate, or leaves it unchanged when mutate returns nil.
+func (n *rimNats) updateStreamSubjects(
+	ctx context.Context,
+	op, name, subject string,
+	mutate func([]string) ([]string, error),
+) error {
+	stream, err := n.js.Stream(ctx, name)
+	if err != nil {
+		return wrapError(op, name, subject, err)
+	}
+
+	info, err := stream.Info(ctx)
+	if err != nil {
+		return wrapError(op, name, subject, err)
+	}
+
+	config := info.Config
+	subjects, err := mutate(config.Subjects)
+	if err != nil {
+		return wrapError(op, name, subject, err)
+	}
+	if subjects == nil {
+		return nil
+	}
+	config.Subjects = subjects
+
+	if err := validateStreamConfig(config); err != nil {
+		return wrapError(op, name, subject, err)
+	}
+
+	if _, err := n.js.UpdateStream(ctx, config); err != nil {
+		n.loggR.Error("🚨 [ rimnats ]: failed to update stream subjects", "stream", name, "subject", subject, "error", err)
+		return wrapError(op, name, subject, err)
+	}
+
+	if n.cfg.Debug {
+		n.loggR.Info("🛠️ [ rimnats ]: updated stream subjects", "stream", name, "subjects", subjects)
+	}
+
+	return nil
+}
+
+// StreamOption adjusts the configuration of a stream created by CreateMirror or CreateSourcedStream,
+// e.g. to set its storage, replicas or retention limits.
+type StreamOption func(*jetstream.StreamConfig)
+
+// CreateMirror creates the stream name as a mirror of sourceStream, or updates it if it already
+// exists. A mirror replicates every message of its source and cannot be published to directly.
+// It returns an error matching ErrStreamNotFound when sourceStream does not exist.
+func (n *rimNats) CreateMirror(ctx context.Context, name, sourceStream string, opts ...StreamOption) (jetstream.Stream, error) {
+	if err := n.checkStreamsExist(ctx, "create mirror", sourceStream); err != nil {
+		return nil, err
+	}
+
+	config := jetstream.StreamConfig{
+		Name:   name,
+		Mirror: &jetstream.StreamSource{Name: sourceStream},
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return n.CreateStream(ctx, config)
+}
+
+// CreateSourcedStream creates the stream name aggregating the messages of sources, or updates it
+// if it already exists, e.g. to fan events from several regions into one stream. It returns an
+// error matching ErrStreamNotFound when one of the sources does not exist.
+func (n *rimNats) CreateSourcedStream(ctx context.Context, name string, sources []string, opts ...StreamOption) (jetstream.Stream, error) {
+	if len(sources) == 0 {
+		return nil, wrapError("create sourced stream", name, "", errors.New("no source streams"))
+	}
+
+	if err := n.checkStreamsExist(ctx, "create sourced stream", sources...); err != nil {
+		return nil, err
+	}
+
+	config := jetstream.StreamConfig{Name: name}
+	for _, source := range sources {
+		config.Sources = append(config.Sources, &jetstream.StreamSource{Name: source})
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return n.CreateStream(ctx, config)
+}
+
+// checkStreamsExist returns an error naming the first of streams that does not exist.
+func (n *rimNats) checkStreamsExist(ctx context.Context, op string, streams ...string) error {
+	for _, name := range streams {
+		if _, err := n.js.Stream(ctx, name); err != nil {
+			return wrapError(op, name, "", fmt.Errorf("source stream %q: %w", name, err))
+		}
+	}
+
+	return nil
+}
+
+// CreateWorkQueue creates the stream name with work-queue retention on subjects, or updates it
+// if it already exists. Every message is removed once a consumer acknowledges it, so each one is
+// processed exactly once by one of the consumers, whose filter subjects must not overlap. The
+// stream is stored on disk and rejects new messages when full instead of dropping unprocessed work.
+func (n *rimNats) CreateWorkQueue(ctx context.Context, name string, subjects []string, opts ...StreamOption) (jetstream.Stream, error) {
+	config := jetstream.StreamConfig{
+		Name:      name,
+		Subjects:  subjects,
+		Retention: jetstream.WorkQueuePolicy,
+		Storage:   jetstream.FileStorage,
+		Discard:   jetstream.DiscardNew,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return n.CreateStream(ctx, config)
+}
+
+// validateStreamConfig reports stream misconfigurations that the server rejects with an
+// obscure error or that make the stream silently drop work.
+func validateStreamConfig(config jetstream.StreamConfig) error {
+	for i, a := range config.Subjects {
+		for _, b := range config.Subjects[i+1:] {
+			if subjectsOverlap(a, b) {
+				return fmt.Errorf("%w: subjects %q and %q overlap", ErrInvalidStreamConfig, a, b)
+			}
+		}
+	}
+
+	if config.Retention == jetstream.WorkQueuePolicy && config.Mirror != nil {
+		return fmt.Errorf("%w: a mirror cannot use work-queue retention", ErrInvalidStreamConfig)
+	}
+
+	if config.Retention != jetstream.LimitsPolicy && len(config.Subjects) == 0 && config.Mirror == nil && len(config.Sources) == 0 {
+		return fmt.Errorf("%w: %s retention requires at least one subject", ErrInvalidStreamConfig, config.Retention)
+	}
+
+	return nil
+}
+
+// checkWorkQueueFilters returns an error matching ErrWorkQueueOverlap when stream uses work-queue
+// retention and config filters on subjects overlapping those of another consumer. The server
+// only allows one consumer per subject on a work queue.
+func checkWorkQueueFilters(ctx context.Context, stream jetstream.Stream, config jetstream.ConsumerConfig) error {
+	if stream.CachedInfo().Config.Retention != jetstream.WorkQueuePolicy {
+		return nil
+	}
+
+	// Consumers without a filter receive every subject of the stream
+	requested := config.FilterSubjects
+	if config.FilterSubject != "" {
+		requested = []string{config.FilterSubject}
+	}
+	if len(requested) == 0 {
+		requested = []string{">"}
+	}
+
+	consumers := stream.ListConsumers(ctx)
+	for info := range consumers.Info() {
+		if info.Name == config.Name || (config.Durable != "" && info.Config.Durable == config.Durable) {
+			continue
+		}
+
+		existing := info.Config.FilterSubjects
+		if info.Config.FilterSubject != "" {
+			existing = []string{info.Config.FilterSubject}
+		}
+		if len(existing) == 0 {
+			existing = []string{">"}
+		}
+
+		for _, a := range requested {
+			for _, b := range existing {
+				if subjectsOverlap(a, b) {
+					return fmt.Errorf("%w: filter %q overlaps filter %q of consumer %q", ErrWorkQueueOverlap, a, b, info.Name)
+				}
+			}
+		}
+	}
+
+	return consumers.Err()
+}
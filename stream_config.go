@@ -0,0 +1,132 @@
+package rimnats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Production defaults applied by NewStreamConfig.
+const (
+	defaultStreamReplicas   = 3
+	defaultDuplicatesWindow = 2 * time.Minute
+)
+
+// StreamConfigBuilder builds a jetstream.StreamConfig with production defaults:
+// limits retention, discarding the oldest messages when full, file storage, three replicas
+// and a two minute duplicate window. Single-server development setups need Replicas(1).
+type StreamConfigBuilder struct {
+	config jetstream.StreamConfig
+}
+
+// NewStreamConfig starts building the configuration of the stream name.
+func NewStreamConfig(name string) *StreamConfigBuilder {
+	return &StreamConfigBuilder{config: jetstream.StreamConfig{
+		Name:       name,
+		Retention:  jetstream.LimitsPolicy,
+		Discard:    jetstream.DiscardOld,
+		Storage:    jetstream.FileStorage,
+		Replicas:   defaultStreamReplicas,
+		Duplicates: defaultDuplicatesWindow,
+	}}
+}
+
+// Description sets the stream description.
+func (b *StreamConfigBuilder) Description(description string) *StreamConfigBuilder {
+	b.config.Description = description
+	return b
+}
+
+// Subjects adds subjects, which may contain wildcards, to the subjects the stream captures.
+func (b *StreamConfigBuilder) Subjects(subjects ...string) *StreamConfigBuilder {
+	b.config.Subjects = append(b.config.Subjects, subjects...)
+	return b
+}
+
+// MaxAge sets how long messages are kept, zero to keep them until another limit is reached.
+func (b *StreamConfigBuilder) MaxAge(age time.Duration) *StreamConfigBuilder {
+	b.config.MaxAge = age
+	return b
+}
+
+// MaxBytes sets the maximum size of the stream in bytes.
+func (b *StreamConfigBuilder) MaxBytes(bytes int64) *StreamConfigBuilder {
+	b.config.MaxBytes = bytes
+	return b
+}
+
+// MaxMsgs sets the maximum number of messages kept by the stream.
+func (b *StreamConfigBuilder) MaxMsgs(msgs int64) *StreamConfigBuilder {
+	b.config.MaxMsgs = msgs
+	return b
+}
+
+// Replicas sets the number of replicas of the stream in a clustered deployment.
+func (b *StreamConfigBuilder) Replicas(replicas int) *StreamConfigBuilder {
+	b.config.Replicas = replicas
+	return b
+}
+
+// Storage sets where the stream stores its messages.
+func (b *StreamConfigBuilder) Storage(storage jetstream.StorageType) *StreamConfigBuilder {
+	b.config.Storage = storage
+	return b
+}
+
+// Retention sets when messages are removed from the stream.
+func (b *StreamConfigBuilder) Retention(retention jetstream.RetentionPolicy) *StreamConfigBuilder {
+	b.config.Retention = retention
+	return b
+}
+
+// Discard sets which messages are discarded once the stream is full.
+func (b *StreamConfigBuilder) Discard(discard jetstream.DiscardPolicy) *StreamConfigBuilder {
+	b.config.Discard = discard
+	return b
+}
+
+// Duplicates sets the window within which messages with the same Nats-Msg-Id are stored once.
+func (b *StreamConfigBuilder) Duplicates(window time.Duration) *StreamConfigBuilder {
+	b.config.Duplicates = window
+	return b
+}
+
+// Apply applies opts to the configuration, e.g. to set fields without a builder method.
+func (b *StreamConfigBuilder) Apply(opts ...StreamOption) *StreamConfigBuilder {
+	for _, opt := range opts {
+		opt(&b.config)
+	}
+
+	return b
+}
+
+// Build validates the configuration and returns it. It fails with ErrInvalidStreamConfig
+// when the name is missing or invalid, no subject is set, replicas are out of range or the
+// configuration is otherwise rejected by CreateStream.
+func (b *StreamConfigBuilder) Build() (jetstream.StreamConfig, error) {
+	config := b.config
+
+	if config.Name == "" {
+		return config, fmt.Errorf("%w: name is required", ErrInvalidStreamConfig)
+	}
+
+	if strings.ContainsAny(config.Name, ".*> \t\n") {
+		return config, fmt.Errorf("%w: name %q must not contain '.', '*', '>' or whitespace", ErrInvalidStreamConfig, config.Name)
+	}
+
+	if len(config.Subjects) == 0 && config.Mirror == nil && len(config.Sources) == 0 {
+		return config, fmt.Errorf("%w: at least one subject is required", ErrInvalidStreamConfig)
+	}
+
+	if config.Replicas < 1 || config.Replicas > 5 {
+		return config, fmt.Errorf("%w: replicas must be between 1 and 5, got %d", ErrInvalidStreamConfig, config.Replicas)
+	}
+
+	if err := validateStreamConfig(config); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
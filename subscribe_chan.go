@@ -0,0 +1,81 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// subscribeChanBuffer is the capacity of the channel returned by SubscribeChan.
+const subscribeChanBuffer = 64
+
+// SubscribeChan works like Subscribe but delivers decoded messages on a buffered channel
+// instead of calling a handler, which suits select loops and fan-out worker pools. The
+// channel is closed once ctx is cancelled. Receivers acknowledge each message through its
+// Msg field; with WithAutoAck messages are acknowledged as soon as they are queued on the
+// channel. Messages that cannot be queued before ctx is cancelled are NAKed.
+//
+// Parameters:
+//   - subject: The NATS subject to subscribe to
+//   - stream: The stream name for the subscription
+//   - durable: The durable name for the subscription, empty for an ephemeral consumer
+//   - factory: A function that creates new instances of the protobuf message type
+//   - opts: Optional subscription options
+//
+// Returns:
+//   - <-chan DecodedMsg: Channel receiving the decoded messages
+//   - error: Returns an error if the subscription setup fails
+func (n *rimNats) SubscribeChan(
+	ctx context.Context,
+	subject string,
+	stream string,
+	durable string,
+	factory func() proto.Message,
+	opts ...SubscribeOption,
+) (<-chan DecodedMsg, error) {
+	return subscribeChan(ctx, func(handler ProtoHandler) (*Subscription, error) {
+		return n.Subscribe(ctx, subject, stream, durable, factory, handler, opts...)
+	})
+}
+
+// subscribeChan subscribes with a handler queuing messages on the returned channel, which is
+// closed once ctx is cancelled and no handler is sending anymore.
+func subscribeChan(ctx context.Context, subscribe func(ProtoHandler) (*Subscription, error)) (<-chan DecodedMsg, error) {
+	out := make(chan DecodedMsg, subscribeChanBuffer)
+
+	var mu sync.RWMutex
+	closed := false
+
+	handler := func(_ context.Context, msg proto.Message, m jetstream.Msg) error {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		if closed {
+			return ctx.Err()
+		}
+
+		select {
+		case out <- DecodedMsg{Proto: msg, Msg: m}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if _, err := subscribe(handler); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		mu.Lock()
+		closed = true
+		close(out)
+		mu.Unlock()
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,154 @@
+package rimnats
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// SubscribeOption configures a single subscription created by Subscribe and its variants.
+type SubscribeOption func(*subscribeOptions)
+
+// subscribeOptions holds the per-subscription settings applied by SubscribeOption.
+type subscribeOptions struct {
+	consumeOpts       []jetstream.PullConsumeOpt   // Options passed to the JetStream pull consumer
+	ackWait           time.Duration                // Time the server waits for an ack before redelivering
+	unackedAge        *unackedAgeMonitor           // Monitor alerting on old unacked messages, nil when disabled
+	decode            func([]byte) ([]byte, error) // Transform applied to raw payloads before decoding, nil when disabled
+	maxDeliver        int                          // Maximum deliveries per message, zero for unlimited
+	deadLetter        DeadLetterHandler            // Called with messages that exhausted their deliveries, may be nil
+	deadLetterSubject *string                      // Subject dead letters are republished to, nil when disabled
+	autoAck           bool                         // Acknowledge messages whose handler succeeds
+	pullExpiry        time.Duration                // Time a single pull request waits for messages, zero for the default
+	pullNoWait        bool                         // Return pulls immediately instead of waiting for messages
+	codec             Codec                        // Codec used to decode messages, nil for the client's event codec
+	factoryFor        factoryResolver              // Resolves the factory per message subject, nil to use the subscription factory
+	deliverPolicy     jetstream.DeliverPolicy      // Where a new consumer starts in the stream
+	startSeq          uint64                       // Stream sequence the consumer starts at with DeliverByStartSequencePolicy
+	startTime         *time.Time                   // Time the consumer starts at with DeliverByStartTimePolicy
+	handlerTimeout    time.Duration                // Deadline of each handler invocation, zero for none and negative for the ack wait
+	maxAckPending     int                          // Maximum unacknowledged messages in flight, zero for the server default
+	concurrency       int                          // Maximum handlers running at once, zero or one to handle messages one at a time
+	consumerName      string                       // Consumer name, empty to use the durable name
+	seen              SeenStore                    // Store skipping messages already processed, nil to handle every delivery
+	inProgress        time.Duration                // Interval between InProgress heartbeats while a handler runs, zero disables them
+}
+
+// newSubscribeOptions applies opts on top of the default subscription settings.
+func newSubscribeOptions(opts []SubscribeOption) *subscribeOptions {
+	options := &subscribeOptions{ackWait: 30 * time.Second}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// WithConsumeOpts passes options such as jetstream.PullMaxMessages through to the JetStream pull consumer.
+func WithConsumeOpts(opts ...jetstream.PullConsumeOpt) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.consumeOpts = append(o.consumeOpts, opts...)
+	}
+}
+
+// WithPayloadTransform transforms raw payloads before they are decoded, e.g. to decompress or
+// decrypt messages published with WithPayloadEncode. Messages whose transform fails are NAKed.
+func WithPayloadTransform(decode func([]byte) ([]byte, error)) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.decode = decode
+	}
+}
+
+// WithAckWait sets how long the server waits for a message to be acknowledged before
+// redelivering it. Raise it for handlers that routinely take longer than the 30 second default.
+func WithAckWait(d time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.ackWait = d
+	}
+}
+
+// WithInProgress keeps slow handlers from having their message redelivered: while the
+// handler runs, the ack wait is reset with m.InProgress every interval. An interval of zero
+// or less uses half the ack wait, see WithAckWait. MaxDeliver is not consumed by heartbeats,
+// so a handler stuck forever keeps its message; combine it with WithHandlerTimeout to bound
+// the work.
+func WithInProgress(interval time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.inProgress = interval
+		if interval <= 0 {
+			o.inProgress = -1
+		}
+	}
+}
+
+// WithAutoAck acknowledges every message whose handler returns nil, so handlers no longer
+// call m.Ack themselves. Messages whose handler returns an error are NAKed as usual.
+func WithAutoAck() SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.autoAck = true
+	}
+}
+
+// WithMaxAckPending limits the consumer to n delivered but unacknowledged messages. Once the
+// limit is reached the server stops delivering until messages are acknowledged, so a low value
+// applies back-pressure to slow handlers. The server default is 1000.
+func WithMaxAckPending(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.maxAckPending = n
+	}
+}
+
+// WithConsumerName names the consumer independently of the durable argument. Ephemeral
+// subscriptions (an empty durable) get a stable, discoverable name instead of a generated one,
+// which helps locating multi-filter consumers. JetStream requires a durable consumer's name to
+// equal its durable name, so for durable subscriptions a different name is rejected with
+// ErrInvalidConsumerConfig before the consumer is created.
+func WithConsumerName(name string) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.consumerName = name
+	}
+}
+
+// WithSeenStore skips messages already processed according to store, so handlers with
+// non-idempotent side effects tolerate redelivery. A message whose Nats-Msg-Id, or stream
+// sequence when it has none, is already in the store is acknowledged without calling the
+// handler; successfully handled messages are added to it. When the store cannot be read
+// the message is handled anyway.
+func WithSeenStore(store SeenStore) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.seen = store
+	}
+}
+
+// WithConcurrency lets up to n handlers of the subscription run at once. Messages are handled
+// one at a time by default; with n greater than one each message is handled on its own
+// goroutine and delivery pauses while n handlers are running, so a rate-limited downstream
+// sees at most n concurrent calls. Combine it with WithMaxAckPending to bound the messages
+// waiting in the client as well.
+func WithConcurrency(n int) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithSubscribeCodec decodes messages with codec instead of the client's event codec.
+// Messages labelled with another known content type in HeaderContentType are still
+// decoded with the matching codec.
+func WithSubscribeCodec(codec Codec) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.codec = codec
+	}
+}
+
+// WithHandlerTimeout gives every handler invocation its own context that expires after d,
+// so handlers can stop working before the message is redelivered. A d of zero or less uses
+// the ack wait, see WithAckWait. Handlers that extend their work with m.InProgress should
+// not rely on this deadline.
+func WithHandlerTimeout(d time.Duration) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.handlerTimeout = d
+		if d <= 0 {
+			o.handlerTimeout = -1
+		}
+	}
+}
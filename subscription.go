@@ -0,0 +1,312 @@
+package rimnats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Subscription is a handle to a consumer or core NATS subscription owned by the client.
+// Stop it to tear down a single subscription while the connection and other subscriptions
+// keep running.
+type Subscription struct {
+	mu       sync.Mutex                  // Guards the fields replaced when the subscription is restored
+	subject  string                      // Subject the subscription listens on
+	stream   string                      // Stream the consumer is bound to, empty for core subscriptions
+	consumer string                      // Consumer name, empty for core subscriptions
+	consume  jetstream.ConsumeContext    // Consume context for JetStream consumers
+	sub      *nats.Subscription          // Subscription for core NATS handlers
+	set      *subscriptionSet            // Set tracking the subscription
+	stop     func()                      // Stops an in-memory subscription, nil otherwise
+	result   ConsumerResult              // Whether subscribing created, updated or reused the consumer
+	info     *jetstream.ConsumerInfo     // Consumer state when the subscription started, nil for core subscriptions
+	restore  func(context.Context) error // Re-establishes the subscription after a reconnect, nil when it needs no restoring
+	stopped  bool                        // Set once the subscription is stopped or drained, so it is not restored
+	progress handlerProgress             // Stream sequences handed to the handler, where a recreated consumer resumes
+}
+
+// handlerProgress tracks the stream sequences of the messages handed to a subscription's
+// handler. With WithConcurrency messages finish out of order, so the sequences still being
+// handled are kept until they finish.
+type handlerProgress struct {
+	mu       sync.Mutex
+	inFlight map[uint64]int // Sequences being handled, counted since a redelivery may overlap
+	handled  uint64         // Highest sequence whose handler finished
+}
+
+// ConsumerResult reports what subscribing did to the JetStream consumer.
+type ConsumerResult string
+
+const (
+	// ConsumerCreated means the consumer did not exist and was created.
+	ConsumerCreated ConsumerResult = "created"
+	// ConsumerUpdated means an existing durable consumer was reused and its configuration changed.
+	ConsumerUpdated ConsumerResult = "updated"
+	// ConsumerUnchanged means an existing durable consumer was reused with the same configuration.
+	ConsumerUnchanged ConsumerResult = "unchanged"
+)
+
+// Subject returns the subject the subscription listens on.
+func (s *Subscription) Subject() string {
+	return s.subject
+}
+
+// Stream returns the stream the consumer is bound to, empty for core subscriptions.
+func (s *Subscription) Stream() string {
+	return s.stream
+}
+
+// Consumer returns the consumer name, empty for core subscriptions.
+func (s *Subscription) Consumer() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.consumer
+}
+
+// ConsumerResult reports whether subscribing created the consumer, updated the configuration
+// of an existing durable or reused it unchanged. It is empty for core subscriptions. Check it
+// after deploying changes to settings such as WithAckWait or WithMaxDeliver.
+func (s *Subscription) ConsumerResult() ConsumerResult {
+	return s.result
+}
+
+// ConsumerInfo returns the consumer state and configuration when the subscription started,
+// nil for core subscriptions.
+func (s *Subscription) ConsumerInfo() *jetstream.ConsumerInfo {
+	return s.info
+}
+
+// Stop stops delivering messages to the handler. Durable consumers are kept on the
+// server, so subscribing again with the same durable resumes where this one stopped.
+func (s *Subscription) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	consume, sub := s.consume, s.sub
+	s.mu.Unlock()
+
+	if consume != nil {
+		consume.Stop()
+	}
+
+	if sub != nil {
+		_ = sub.Unsubscribe()
+	}
+
+	if s.stop != nil {
+		s.stop()
+	}
+
+	if s.set != nil {
+		s.set.remove(s)
+	}
+}
+
+// drain drains the JetStream consumer, returning its consume context, or nil for core
+// subscriptions. A drained subscription is no longer restored after a reconnect.
+func (s *Subscription) drain() jetstream.ConsumeContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.consume == nil {
+		return nil
+	}
+
+	s.stopped = true
+	s.consume.Drain()
+
+	return s.consume
+}
+
+// consumeContext returns the current consume context, nil for core subscriptions.
+func (s *Subscription) consumeContext() jetstream.ConsumeContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.consume
+}
+
+// awaitClosed blocks until the consume context closes without having been replaced by a
+// restored one.
+func (s *Subscription) awaitClosed() {
+	for {
+		consume := s.consumeContext()
+		<-consume.Closed()
+		if s.consumeContext() == consume {
+			return
+		}
+	}
+}
+
+// startHandling records that m is handed to the handler and returns its stream sequence,
+// which is passed to finishHandling once the handler is done. It returns 0 when m carries
+// no metadata.
+func (s *Subscription) startHandling(m jetstream.Msg) uint64 {
+	meta, err := m.Metadata()
+	if err != nil {
+		return 0
+	}
+
+	p := &s.progress
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.inFlight == nil {
+		p.inFlight = make(map[uint64]int)
+	}
+	p.inFlight[meta.Sequence.Stream]++
+
+	return meta.Sequence.Stream
+}
+
+// finishHandling records that the handler is done with the message at stream sequence seq.
+func (s *Subscription) finishHandling(seq uint64) {
+	if seq == 0 {
+		return
+	}
+
+	p := &s.progress
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.inFlight[seq]--; p.inFlight[seq] <= 0 {
+		delete(p.inFlight, seq)
+	}
+	p.handled = max(p.handled, seq)
+}
+
+// resumeSequence returns the stream sequence a recreated consumer resumes at: the lowest one
+// still being handled, or the one after the last handled message when none is. It returns 0
+// before any message has been handed to the handler.
+func (s *Subscription) resumeSequence() uint64 {
+	p := &s.progress
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var lowest uint64
+	for seq := range p.inFlight {
+		if lowest == 0 || seq < lowest {
+			lowest = seq
+		}
+	}
+
+	if lowest == 0 && p.handled > 0 {
+		return p.handled + 1
+	}
+
+	return lowest
+}
+
+// replaceConsumer swaps in the consume context of a recreated consumer and stops the
+// previous one. The new context is stopped instead when the subscription was stopped.
+func (s *Subscription) replaceConsumer(consumer string, consume jetstream.ConsumeContext) {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		consume.Stop()
+		return
+	}
+
+	previous := s.consume
+	s.consumer, s.consume = consumer, consume
+	s.mu.Unlock()
+
+	previous.Stop()
+}
+
+// replaceSub swaps in a new core NATS subscription, unsubscribing it instead when the
+// subscription was stopped.
+func (s *Subscription) replaceSub(sub *nats.Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		_ = sub.Unsubscribe()
+		return
+	}
+
+	s.sub = sub
+}
+
+// coreSub returns the core NATS subscription, nil for JetStream consumers.
+func (s *Subscription) coreSub() *nats.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sub
+}
+
+// subscriptionSet tracks the active subscriptions of a client.
+type subscriptionSet struct {
+	mu      sync.Mutex
+	entries map[*Subscription]struct{}
+	metrics *Metrics
+}
+
+func newSubscriptionSet(metrics *Metrics) *subscriptionSet {
+	return &subscriptionSet{entries: make(map[*Subscription]struct{}), metrics: metrics}
+}
+
+// add starts tracking s. JetStream consumers are untracked automatically once they stop.
+func (set *subscriptionSet) add(s *Subscription) {
+	s.set = set
+
+	set.mu.Lock()
+	set.entries[s] = struct{}{}
+	set.updateMetrics()
+	set.mu.Unlock()
+
+	if s.consumeContext() != nil {
+		go func() {
+			s.awaitClosed()
+			set.remove(s)
+		}()
+	}
+}
+
+// remove stops tracking s.
+func (set *subscriptionSet) remove(s *Subscription) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	delete(set.entries, s)
+	set.updateMetrics()
+}
+
+// list returns the currently tracked subscriptions.
+func (set *subscriptionSet) list() []*Subscription {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	subs := make([]*Subscription, 0, len(set.entries))
+	for s := range set.entries {
+		subs = append(subs, s)
+	}
+
+	return subs
+}
+
+// clear stops tracking every subscription.
+func (set *subscriptionSet) clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	clear(set.entries)
+	set.updateMetrics()
+}
+
+// updateMetrics reports the tracked counts. Callers must hold set.mu.
+func (set *subscriptionSet) updateMetrics() {
+	var subscriptions, consumers int
+	for s := range set.entries {
+		if s.consumeContext() != nil {
+			consumers++
+		} else {
+			subscriptions++
+		}
+	}
+
+	set.metrics.setActive(subscriptions, consumers)
+}
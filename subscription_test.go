@@ -0,0 +1,33 @@
+package rimnats
+
+import (
+	"testing"
+)
+
+func TestResumeSequenceWaitsForConcurrentHandlers(t *testing.T) {
+	sub := &Subscription{}
+	if seq := sub.resumeSequence(); seq != 0 {
+		t.Fatalf("resume sequence before any message = %d, want 0", seq)
+	}
+
+	// Three messages are handled concurrently and the last one finishes first
+	var seqs []uint64
+	for _, stream := range []uint64{1, 2, 3} {
+		seqs = append(seqs, sub.startHandling(&memoryMsg{sequence: stream}))
+	}
+
+	sub.finishHandling(seqs[2])
+	if seq := sub.resumeSequence(); seq != 1 {
+		t.Errorf("resume sequence with 1 and 2 in flight = %d, want 1", seq)
+	}
+
+	sub.finishHandling(seqs[0])
+	if seq := sub.resumeSequence(); seq != 2 {
+		t.Errorf("resume sequence with 2 in flight = %d, want 2", seq)
+	}
+
+	sub.finishHandling(seqs[1])
+	if seq := sub.resumeSequence(); seq != 4 {
+		t.Errorf("resume sequence with all handled = %d, want 4", seq)
+	}
+}
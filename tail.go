@@ -0,0 +1,146 @@
+package rimnats
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// TailLast returns the most recent messages published on a subject, oldest first.
+// It looks up the last message on the subject and starts a temporary consumer just
+// before it, widening the start sequence until the consumer covers count messages or
+// the start of the stream, then deletes the consumer once done. Intended for debugging
+// and inspection tools.
+//
+// Parameters:
+//   - stream: The stream holding the subject
+//   - subject: The subject (or wildcard) to read from
+//   - count: The maximum number of messages to return
+//   - factory: A function that creates new instances of the protobuf message type
+//
+// Returns:
+//   - []proto.Message: The decoded messages in stream order
+//   - error: Returns an error if the stream cannot be read, a message fails to decode or ctx ends
+func (n *rimNats) TailLast(ctx context.Context, stream, subject string, count int, factory func() proto.Message) ([]proto.Message, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+
+	jetStream, err := n.js.Stream(ctx, stream)
+	if err != nil {
+		return nil, wrapError("tail", stream, subject, err)
+	}
+
+	last, err := jetStream.GetLastMsgForSubject(ctx, subject)
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("tail", stream, subject, err)
+	}
+
+	consumer, err := n.tailConsumer(ctx, jetStream, subject, last.Sequence, uint64(count))
+	if err != nil {
+		return nil, wrapError("tail", stream, subject, err)
+	}
+
+	defer func() {
+		if err := jetStream.DeleteConsumer(context.Background(), consumer.CachedInfo().Name); err != nil && n.cfg.Debug {
+			n.loggR.Info("🚨 [ rimnats ]: failed to delete tail consumer", "stream", stream, "error", err)
+		}
+	}()
+
+	// Collect the messages up to the last one looked up, later publishes are ignored
+	total := consumer.CachedInfo().NumPending
+	received := make([]jetstream.Msg, 0, total)
+	for done := false; !done && uint64(len(received)) < total; {
+		maxWait := 5 * time.Second
+		if deadline, ok := ctx.Deadline(); ok {
+			maxWait = time.Until(deadline)
+		}
+		if err := ctx.Err(); err != nil || maxWait <= 0 {
+			return nil, wrapError("tail", stream, subject, cmp.Or(err, context.DeadlineExceeded))
+		}
+
+		batch, err := consumer.Fetch(int(min(total-uint64(len(received)), 256)), jetstream.FetchMaxWait(maxWait))
+		if err != nil {
+			return nil, wrapError("tail", stream, subject, err)
+		}
+
+		fetched := 0
+		for m := range batch.Messages() {
+			fetched++
+			meta, err := m.Metadata()
+			if err != nil {
+				return nil, wrapError("tail", stream, subject, err)
+			}
+			if meta.Sequence.Stream > last.Sequence {
+				done = true
+				continue
+			}
+
+			received = append(received, m)
+			done = done || meta.Sequence.Stream == last.Sequence
+		}
+
+		if err := batch.Error(); err != nil {
+			return nil, wrapError("tail", stream, subject, err)
+		}
+
+		// Messages may have been removed by retention since the consumer was created
+		if fetched == 0 {
+			break
+		}
+	}
+
+	if len(received) > count {
+		received = received[len(received)-count:]
+	}
+
+	messages := make([]proto.Message, 0, len(received))
+	for _, m := range received {
+		msg := factory()
+		if err := codecFor(n.cfg.EventCodec, m.Headers().Get(HeaderContentType)).Unmarshal(m.Data(), msg); err != nil {
+			return nil, unmarshalError(err)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// tailConsumer creates a consumer on subject starting close enough to lastSeq to deliver
+// at least count messages. The start sequence moves back by a doubling window until the
+// consumer has count messages pending or starts at the first sequence of the stream.
+func (n *rimNats) tailConsumer(ctx context.Context, jetStream jetstream.Stream, subject string, lastSeq, count uint64) (jetstream.Consumer, error) {
+	for window := count; ; window *= 2 {
+		start := uint64(1)
+		if lastSeq > window {
+			start = lastSeq - window + 1
+		}
+
+		consumer, err := jetStream.CreateConsumer(ctx, jetstream.ConsumerConfig{
+			FilterSubject:     subject,
+			DeliverPolicy:     jetstream.DeliverByStartSequencePolicy,
+			OptStartSeq:       start,
+			AckPolicy:         jetstream.AckNonePolicy,
+			InactiveThreshold: time.Minute,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if consumer.CachedInfo().NumPending >= count || start == 1 {
+			return consumer, nil
+		}
+
+		if err := jetStream.DeleteConsumer(ctx, consumer.CachedInfo().Name); err != nil && n.cfg.Debug {
+			n.loggR.Info("🚨 [ rimnats ]: failed to delete tail consumer", "subject", subject, "error", err)
+		}
+	}
+}
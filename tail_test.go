@@ -0,0 +1,75 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTailLast(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	stream := createTestStream(t, client, "products", "product.>")
+
+	// Other subjects are interleaved so the start sequence has to be widened
+	for i := range 20 {
+		if _, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		if _, err := client.Publish(ctx, "product.updated", &v1.ProductCreated{Id: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	msgs, err := client.TailLast(ctx, "products", "product.created", 10, func() proto.Message { return &v1.ProductCreated{} })
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+
+	if len(msgs) != 10 {
+		t.Fatalf("got %d messages, want 10", len(msgs))
+	}
+	for i, msg := range msgs {
+		if got, want := msg.(*v1.ProductCreated).GetId(), fmt.Sprint(10+i); got != want {
+			t.Errorf("message %d has id %s, want %s", i, got, want)
+		}
+	}
+
+	// Asking for more than the subject holds returns everything
+	msgs, err = client.TailLast(ctx, "products", "product.created", 50, func() proto.Message { return &v1.ProductCreated{} })
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	if len(msgs) != 20 {
+		t.Fatalf("got %d messages, want 20", len(msgs))
+	}
+
+	// The temporary consumers are removed
+	info, err := stream.Info(ctx)
+	if err != nil {
+		t.Fatalf("stream info: %v", err)
+	}
+	if info.State.Consumers != 0 {
+		t.Errorf("stream has %d consumers left, want 0", info.State.Consumers)
+	}
+}
+
+func TestTailLastEmptySubject(t *testing.T) {
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "products", "product.>")
+
+	msgs, err := client.TailLast(context.Background(), "products", "product.deleted", 10, func() proto.Message { return &v1.ProductCreated{} })
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	if len(msgs) != 0 {
+		t.Fatalf("got %d messages, want none", len(msgs))
+	}
+}
@@ -0,0 +1,105 @@
+package rimnats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// TypedHandler processes a decoded message of type T along with its NATS message context.
+type TypedHandler[T proto.Message] func(ctx context.Context, msg T, m jetstream.Msg) error
+
+// SubscribeTyped works like Client.Subscribe but decodes messages into T and passes them to
+// the handler already typed, so handlers no longer assert msg.(*v1.Event) themselves.
+// T must be a generated protobuf message pointer such as *v1.Event.
+//
+// Example:
+//
+//	rimnats.SubscribeTyped(ctx, client, "product.created", "product_stream", "product_service",
+//		func(ctx context.Context, event *v1.Event, m jetstream.Msg) error {
+//			return m.Ack()
+//		})
+func SubscribeTyped[T proto.Message](
+	ctx context.Context,
+	client Client,
+	subject string,
+	stream string,
+	durable string,
+	handler TypedHandler[T],
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	return client.Subscribe(ctx, subject, stream, durable, factoryOf[T](), func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+		typed, ok := msg.(T)
+		if !ok {
+			return fmt.Errorf("rimnats: unexpected message type %T", msg)
+		}
+
+		return handler(ctx, typed, m)
+	}, opts...)
+}
+
+// RequestTyped works like Client.Request but returns the reply already typed as Resp, so
+// callers no longer assert resp.(*v1.SayHelloResponse) themselves.
+//
+// Example:
+//
+//	resp, err := rimnats.RequestTyped[*v1.SayHelloRequest, *v1.SayHelloResponse](ctx, client,
+//		"example.say.hello", &v1.SayHelloRequest{Name: "Joey"}, 3*time.Second)
+func RequestTyped[Req, Resp proto.Message](
+	ctx context.Context,
+	client Client,
+	subject string,
+	req Req,
+	timeout time.Duration,
+	opts ...RequestOption,
+) (Resp, error) {
+	var zero Resp
+
+	resp, err := client.Request(ctx, subject, req, factoryOf[Resp](), timeout, opts...)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := resp.(Resp)
+	if !ok {
+		return zero, fmt.Errorf("rimnats: unexpected response type %T", resp)
+	}
+
+	return typed, nil
+}
+
+// ReplyTyped works like Client.Reply but decodes requests into Req and passes them to the
+// handler already typed.
+//
+// Example:
+//
+//	rimnats.ReplyTyped(client, "example.say.hello",
+//		func(ctx context.Context, req *v1.SayHelloRequest) (*v1.SayHelloResponse, error) {
+//			return &v1.SayHelloResponse{Message: "Hello " + req.GetName()}, nil
+//		})
+func ReplyTyped[Req, Resp proto.Message](
+	client Client,
+	subject string,
+	handler func(ctx context.Context, req Req) (Resp, error),
+	opts ...ReplyOption,
+) error {
+	return client.Reply(subject, factoryOf[Req](), func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		typed, ok := req.(Req)
+		if !ok {
+			return nil, fmt.Errorf("rimnats: unexpected request type %T", req)
+		}
+
+		return handler(ctx, typed)
+	}, opts...)
+}
+
+// factoryOf returns a factory creating new, empty messages of type T.
+func factoryOf[T proto.Message]() func() proto.Message {
+	var zero T
+	return func() proto.Message {
+		return zero.ProtoReflect().New().Interface()
+	}
+}
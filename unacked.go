@@ -0,0 +1,111 @@
+package rimnats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// UnackedAlert describes a consumer whose oldest unacknowledged message exceeded the alert threshold.
+type UnackedAlert struct {
+	Stream     string        // Stream the consumer reads from
+	Consumer   string        // Name of the consumer
+	Sequence   uint64        // Stream sequence of the oldest unacknowledged message
+	Age        time.Duration // Time since the oldest unacknowledged message was stored
+	AckPending int           // Number of delivered messages awaiting acknowledgment
+}
+
+// unackedAgeMonitor periodically checks the age of a consumer's oldest unacknowledged message.
+type unackedAgeMonitor struct {
+	threshold time.Duration      // Age above which onAlert is called
+	interval  time.Duration      // Time between checks
+	onAlert   func(UnackedAlert) // Callback invoked on every check exceeding the threshold
+}
+
+// WithUnackedAgeAlert starts a background monitor that checks the consumer's oldest unacknowledged
+// message, found via the consumer's ack floor, and calls onAlert whenever it has been waiting longer
+// than threshold. A message that stays unacked that long usually means a stuck or failing handler.
+// The check runs every threshold/2, clamped between 100ms and one second, until the subscription
+// stops. When onAlert is nil the alert is logged as a warning instead.
+func WithUnackedAgeAlert(threshold time.Duration, onAlert func(UnackedAlert)) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.unackedAge = &unackedAgeMonitor{
+			threshold: threshold,
+			interval:  min(max(threshold/2, 100*time.Millisecond), time.Second),
+			onAlert:   onAlert,
+		}
+	}
+}
+
+// monitorUnackedAge runs the unacked age monitor for consumer until the consume context closes.
+func (n *rimNats) monitorUnackedAge(
+	monitor *unackedAgeMonitor,
+	stream jetstream.Stream,
+	consumer jetstream.Consumer,
+	consumeCtx jetstream.ConsumeContext,
+	durable string,
+	filter string,
+) {
+	ticker := time.NewTicker(monitor.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-consumeCtx.Closed():
+			return
+		case <-ticker.C:
+		}
+
+		alert, ok, err := n.oldestUnacked(stream, consumer, filter)
+		if err != nil {
+			if n.cfg.Debug {
+				n.loggR.Info("🚨 [ rimnats ]: failed to check unacked message age", "stream", stream.CachedInfo().Config.Name, "consumer", durable, "error", err)
+			}
+			continue
+		}
+
+		if !ok || alert.Age <= monitor.threshold {
+			continue
+		}
+
+		if monitor.onAlert == nil {
+			n.loggR.Warn("⏳ [ rimnats ]: message unacked past threshold", "stream", alert.Stream, "consumer", alert.Consumer, "sequence", alert.Sequence, "age", alert.Age, "threshold", monitor.threshold)
+			continue
+		}
+
+		monitor.onAlert(alert)
+	}
+}
+
+// oldestUnacked looks up the oldest unacknowledged message of consumer. The returned bool is false
+// when nothing is awaiting acknowledgment.
+func (n *rimNats) oldestUnacked(stream jetstream.Stream, consumer jetstream.Consumer, filter string) (UnackedAlert, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := consumer.Info(ctx)
+	if err != nil {
+		return UnackedAlert{}, false, err
+	}
+
+	alert := UnackedAlert{Stream: info.Stream, Consumer: info.Name, AckPending: info.NumAckPending}
+	if info.NumAckPending == 0 {
+		return alert, false, nil
+	}
+
+	var opts []jetstream.GetMsgOpt
+	if filter != "" {
+		opts = append(opts, jetstream.WithGetMsgSubject(filter))
+	}
+
+	raw, err := stream.GetMsg(ctx, info.AckFloor.Stream+1, opts...)
+	if err != nil {
+		return alert, false, err
+	}
+
+	alert.Sequence = raw.Sequence
+	alert.Age = n.cfg.clock().Sub(raw.Time)
+
+	return alert, true, nil
+}
@@ -0,0 +1,57 @@
+package rimnats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	v1 "github.com/rimdesk/rimnats-go/gen/rimdesk/rimnats/v1"
+	"github.com/rimdesk/rimnats-go/rimnatstest"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnackedAgeAlert(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, rimnatstest.StartServer(t))
+	createTestStream(t, client, "products", "product.>")
+
+	alerts := make(chan UnackedAlert, 1)
+	_, err := client.Subscribe(ctx, "product.created", "products", "unacked_test", func() proto.Message { return &v1.ProductCreated{} },
+		func(ctx context.Context, msg proto.Message, m jetstream.Msg) error {
+			time.Sleep(20 * time.Millisecond)
+			return errors.New("always fails")
+		},
+		WithUnackedAgeAlert(200*time.Millisecond, func(alert UnackedAlert) {
+			select {
+			case alerts <- alert:
+			default:
+			}
+		}))
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	ack, err := client.Publish(ctx, "product.created", &v1.ProductCreated{Id: "stuck"})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	select {
+	case alert := <-alerts:
+		if alert.Stream != "products" || alert.Consumer != "unacked_test" {
+			t.Errorf("alert for %s/%s, want products/unacked_test", alert.Stream, alert.Consumer)
+		}
+		if alert.Sequence != ack.Sequence {
+			t.Errorf("alert sequence = %d, want %d", alert.Sequence, ack.Sequence)
+		}
+		if alert.Age <= 200*time.Millisecond {
+			t.Errorf("alert age = %v, want more than the threshold", alert.Age)
+		}
+	case <-ctx.Done():
+		t.Fatal("unacked age alert did not fire")
+	}
+}